@@ -1,12 +1,9 @@
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
-	"strings"
-
 	"github.com/spf13/cobra"
 	"github.com/tylerbryy/verity-cli/pkg/client"
+	"github.com/tylerbryy/verity-cli/pkg/output"
 )
 
 var batchCmd = &cobra.Command{
@@ -14,36 +11,36 @@ var batchCmd = &cobra.Command{
 	Short: "Batch lookup multiple medical codes",
 	Long:  "Look up multiple medical codes (CPT, HCPCS, ICD-10, NDC) in a single request",
 	Args:  cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		c := client.New(getAPIKey(), getBaseURL())
-
-		reqBody := map[string]interface{}{
-			"codes": args,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
 		}
 
 		system, _ := cmd.Flags().GetString("system")
-		if system != "" {
-			reqBody["code_system"] = system
-		}
-
 		include, _ := cmd.Flags().GetStringSlice("include")
-		if len(include) > 0 {
-			reqBody["include"] = strings.Join(include, ",")
-		}
 
-		var result map[string]interface{}
-		if err := c.Post("/codes/batch", reqBody, &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
-		}
+		ctx, cancel := commandContext(cmd, defaultTimeout)
+		defer cancel()
 
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printBatchResult(result)
+		result, err := c.BatchLookup(ctx, client.BatchLookupRequest{
+			Codes:      args,
+			CodeSystem: system,
+			Include:    include,
+		})
+		if err != nil {
+			return err
 		}
+
+		return render(&output.View{
+			Data:    result,
+			Records: result.Data,
+			Columns: batchColumns,
+			Sheets: func() []output.Sheet {
+				return batchSheets(result.Data)
+			},
+			Empty: "No results found",
+		})
 	},
 }
 
@@ -54,34 +51,47 @@ func init() {
 	batchCmd.Flags().StringSliceP("include", "i", []string{}, "Include additional data (rvu, policies)")
 }
 
-func printBatchResult(result map[string]interface{}) {
-	data, ok := result["data"].([]interface{})
-	if !ok || len(data) == 0 {
-		fmt.Println("No results found")
-		return
-	}
-
-	fmt.Printf("%-12s %-10s %-8s %s\n", "CODE", "SYSTEM", "FOUND", "DESCRIPTION")
-	fmt.Printf("%-12s %-10s %-8s %s\n", "----", "------", "-----", "-----------")
+var batchColumns = []output.Column{
+	{Field: "code"},
+	{Field: "code_system", Header: "SYSTEM"},
+	{Field: "found"},
+	{Field: "description"},
+	{Field: "rvu.work_rvu", Header: "WORK_RVU", Wide: true},
+	{Field: "rvu.non_facility_price", Header: "NON_FACILITY", Wide: true},
+	{Field: "rvu.facility_price", Header: "FACILITY", Wide: true},
+}
 
-	for _, item := range data {
-		entry, ok := item.(map[string]interface{})
-		if !ok {
+// batchSheets lists the codes on one sheet, then gives each code with
+// matching policies a sheet of them.
+func batchSheets(data []client.CodeLookup) []output.Sheet {
+	sheets := []output.Sheet{{
+		Name:    "Codes",
+		Records: data,
+		Columns: []output.Column{
+			{Field: "code", Header: "Code"},
+			{Field: "code_system", Header: "System"},
+			{Field: "found", Header: "Found"},
+			{Field: "description", Header: "Description"},
+			{Field: "rvu.work_rvu", Header: "Work RVU"},
+			{Field: "rvu.non_facility_price", Header: "Non-Facility Price"},
+			{Field: "rvu.facility_price", Header: "Facility Price"},
+		},
+	}}
+	for _, entry := range data {
+		if len(entry.Policies) == 0 {
 			continue
 		}
-
-		code := fmt.Sprintf("%v", entry["code"])
-		system := fmt.Sprintf("%v", entry["code_system"])
-		found := fmt.Sprintf("%v", entry["found"])
-
-		description := ""
-		if desc, ok := entry["description"].(string); ok {
-			description = desc
-			if len(description) > 60 {
-				description = description[:57] + "..."
-			}
-		}
-
-		fmt.Printf("%-12s %-10s %-8s %s\n", code, system, found, description)
+		sheets = append(sheets, output.Sheet{
+			Name:    entry.Code,
+			Records: entry.Policies,
+			Columns: []output.Column{
+				{Field: "policy_id", Header: "Policy ID"},
+				{Field: "title", Header: "Title"},
+				{Field: "policy_type", Header: "Type"},
+				{Field: "jurisdiction", Header: "Jurisdiction"},
+				{Field: "disposition", Header: "Disposition"},
+			},
+		})
 	}
+	return sheets
 }
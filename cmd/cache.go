@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tylerbryy/verity-cli/pkg/client"
+	"github.com/tylerbryy/verity-cli/pkg/output"
+)
+
+var (
+	noCache      bool
+	refreshCache bool
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the response cache",
+	Long: `Inspect and clean up the on-disk response cache.
+
+The cache is off unless the cache config key (or VERITY_CACHE) is true.
+Code lookups, jurisdictions and spending are kept for 24 hours, policies and
+criteria searches for an hour. Health checks, webhooks, prior-auth, policy
+comparisons and coverage evaluations are never cached. Entries are kept per
+API key.
+
+Separately, GET responses that carry an ETag or Last-Modified header are kept
+so later requests can be sent conditionally; a 304 Not Modified reply is
+answered from the stored copy. Set conditional_requests to false to turn
+this off.`,
+}
+
+// cacheStatus is the output of cache stats.
+type cacheStatus struct {
+	Responses  client.CacheStats `json:"responses"`
+	Validators client.CacheStats `json:"validators"`
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache size and entry counts",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := openCache()
+		if err != nil {
+			return err
+		}
+		validators, err := openValidators()
+		if err != nil {
+			return err
+		}
+
+		var stats cacheStatus
+		if stats.Responses, err = cache.Stats(); err != nil {
+			return err
+		}
+		if stats.Validators, err = validators.Stats(); err != nil {
+			return err
+		}
+
+		return render(&output.View{
+			Data: stats,
+			Table: func(w io.Writer) {
+				printCacheStats(w, stats)
+			},
+		})
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every cached response",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := openCache()
+		if err != nil {
+			return err
+		}
+
+		validators, err := openValidators()
+		if err != nil {
+			return err
+		}
+
+		removed, err := cache.Clear()
+		if err != nil {
+			return err
+		}
+		stored, err := validators.Clear()
+		if err != nil {
+			return err
+		}
+		return renderRemoved(removed+stored, "Removed %d cached responses\n")
+	},
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove expired cached responses",
+	Long: `Remove expired cached responses, and stored responses for conditional
+requests that have not been used for 30 days.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := openCache()
+		if err != nil {
+			return err
+		}
+
+		validators, err := openValidators()
+		if err != nil {
+			return err
+		}
+
+		removed, err := cache.Prune()
+		if err != nil {
+			return err
+		}
+		stale, err := validators.Prune()
+		if err != nil {
+			return err
+		}
+		return renderRemoved(removed+stale, "Removed %d expired responses\n")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Do not read or write the response cache, and send no conditional requests")
+	rootCmd.PersistentFlags().BoolVar(&refreshCache, "refresh", false, "Ignore cached responses and store fresh ones")
+}
+
+// cacheDir returns the cache_dir config key, or the verity directory under
+// the user cache directory.
+func cacheDir() (string, error) {
+	if dir := viper.GetString("cache_dir"); dir != "" {
+		return dir, nil
+	}
+	dir, err := client.DefaultCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locating cache directory: %w", err)
+	}
+	return dir, nil
+}
+
+// openCache returns the response cache. Per-endpoint TTLs can be
+// overridden with the cache_ttls config key, e.g. {"/jurisdictions": "48h"}
+// or {"POST /codes/batch": "1h"}.
+func openCache() (*client.Cache, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	cache := client.NewCache(dir)
+	for key, value := range viper.GetStringMapString("cache_ttls") {
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("cache_ttls: invalid TTL %q for %s", value, key)
+		}
+		// Config keys come back lowercased; methods are matched in
+		// upper case.
+		if method, path, ok := strings.Cut(key, " "); ok {
+			key = strings.ToUpper(method) + " " + path
+		}
+		cache.TTLs[key] = ttl
+	}
+	cache.Refresh = refreshCache
+	return cache, nil
+}
+
+// openValidators returns the store of responses kept for conditional
+// requests.
+func openValidators() (*client.Validators, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return client.NewValidators(filepath.Join(dir, "validators")), nil
+}
+
+// clientCache returns the cache for API calls, or nil when caching is off.
+func clientCache() (*client.Cache, error) {
+	if noCache || !viper.GetBool("cache") {
+		return nil, nil
+	}
+	return openCache()
+}
+
+// clientValidators returns the validator store for API calls, or nil when
+// conditional requests are off.
+func clientValidators() (*client.Validators, error) {
+	if noCache || !viper.GetBool("conditional_requests") {
+		return nil, nil
+	}
+	return openValidators()
+}
+
+func printCacheStats(w io.Writer, stats cacheStatus) {
+	fmt.Fprintf(w, "Directory: %s\n", stats.Responses.Dir)
+	fmt.Fprintf(w, "Responses: %d (%d expired), %s\n", stats.Responses.Entries, stats.Responses.Expired, byteSize(stats.Responses.Bytes))
+	if stats.Responses.Oldest != nil && stats.Responses.Newest != nil {
+		fmt.Fprintf(w, "Oldest: %s\n", stats.Responses.Oldest.Local().Format(time.RFC3339))
+		fmt.Fprintf(w, "Newest: %s\n", stats.Responses.Newest.Local().Format(time.RFC3339))
+	}
+	fmt.Fprintf(w, "Conditional request store: %d, %s\n", stats.Validators.Entries, byteSize(stats.Validators.Bytes))
+}
+
+func renderRemoved(removed int, message string) error {
+	return render(&output.View{
+		Data: map[string]int{"removed": removed},
+		Table: func(w io.Writer) {
+			fmt.Fprintf(w, message, removed)
+		},
+	})
+}
+
+// byteSize formats n as a human-readable size such as 1.5 MB.
+func byteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -1,11 +1,13 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	"github.com/tylerbryy/verity-cli/pkg/client"
+	"github.com/tylerbryy/verity-cli/pkg/output"
 )
 
 var checkCmd = &cobra.Command{
@@ -13,48 +15,34 @@ var checkCmd = &cobra.Command{
 	Short: "Look up a medical code",
 	Long:  "Look up a medical code (CPT, HCPCS, ICD-10, NDC) and get coverage information",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		code := args[0]
-
-		c := client.New(getAPIKey(), getBaseURL())
-
-		path := fmt.Sprintf("/codes/lookup?code=%s", code)
-
-		include, _ := cmd.Flags().GetStringSlice("include")
-		if len(include) > 0 {
-			includeStr := ""
-			for i, inc := range include {
-				if i > 0 {
-					includeStr += ","
-				}
-				includeStr += inc
-			}
-			path += "&include=" + includeStr
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
 		}
 
+		include, _ := cmd.Flags().GetStringSlice("include")
 		jurisdiction, _ := cmd.Flags().GetString("jurisdiction")
-		if jurisdiction != "" {
-			path += "&jurisdiction=" + jurisdiction
-		}
-
 		fuzzy, _ := cmd.Flags().GetBool("fuzzy")
-		if !fuzzy {
-			path += "&fuzzy=false"
-		}
 
-		var result map[string]interface{}
-		if err := c.Get(path, &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
-		}
+		ctx, cancel := commandContext(cmd, defaultTimeout)
+		defer cancel()
 
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printCodeResult(result)
+		result, err := c.LookupCode(ctx, args[0], &client.LookupOptions{
+			Include:      include,
+			Jurisdiction: jurisdiction,
+			Exact:        !fuzzy,
+		})
+		if err != nil {
+			return err
 		}
+
+		return render(&output.View{
+			Data: result,
+			Table: func(w io.Writer) {
+				printCodeResult(w, result.Data)
+			},
+		})
 	},
 }
 
@@ -66,40 +54,33 @@ func init() {
 	checkCmd.Flags().BoolP("fuzzy", "f", true, "Enable fuzzy matching")
 }
 
-func printCodeResult(result map[string]interface{}) {
-	data, ok := result["data"].(map[string]interface{})
-	if !ok {
-		fmt.Println("Invalid response format")
-		return
-	}
-
-	fmt.Printf("Code: %v\n", data["code"])
-	fmt.Printf("System: %v\n", data["code_system"])
-	fmt.Printf("Found: %v\n", data["found"])
+func printCodeResult(w io.Writer, data client.CodeLookup) {
+	fmt.Fprintf(w, "Code: %s\n", data.Code)
+	fmt.Fprintf(w, "System: %s\n", data.CodeSystem)
+	fmt.Fprintf(w, "Found: %s\n", good(data.Found, strconv.FormatBool(data.Found)))
 
-	if desc, ok := data["description"].(string); ok && desc != "" {
-		fmt.Printf("Description: %s\n", desc)
+	if data.Description != "" {
+		fmt.Fprintf(w, "Description: %s\n", data.Description)
 	}
 
-	if rvu, ok := data["rvu"].(map[string]interface{}); ok {
-		fmt.Println("\nRVU Data:")
-		if workRvu, ok := rvu["work_rvu"].(string); ok {
-			fmt.Printf("  Work RVU: %s\n", workRvu)
+	if rvu := data.RVU; rvu != nil {
+		fmt.Fprintln(w, "\nRVU Data:")
+		if rvu.WorkRVU != "" {
+			fmt.Fprintf(w, "  Work RVU: %s\n", rvu.WorkRVU)
 		}
-		if price, ok := rvu["non_facility_price"].(string); ok {
-			fmt.Printf("  Non-Facility Price: $%s\n", price)
+		if rvu.NonFacilityPrice != "" {
+			fmt.Fprintf(w, "  Non-Facility Price: $%s\n", rvu.NonFacilityPrice)
 		}
-		if price, ok := rvu["facility_price"].(string); ok {
-			fmt.Printf("  Facility Price: $%s\n", price)
+		if rvu.FacilityPrice != "" {
+			fmt.Fprintf(w, "  Facility Price: $%s\n", rvu.FacilityPrice)
 		}
 	}
 
-	if policies, ok := data["policies"].([]interface{}); ok && len(policies) > 0 {
-		fmt.Println("\nPolicies:")
-		for _, p := range policies {
-			policy := p.(map[string]interface{})
-			fmt.Printf("  - %s (%s): %s\n", policy["policy_id"], policy["policy_type"], policy["disposition"])
-			fmt.Printf("    %s\n", policy["title"])
+	if len(data.Policies) > 0 {
+		fmt.Fprintln(w, "\nPolicies:")
+		for _, policy := range data.Policies {
+			fmt.Fprintf(w, "  - %s (%s): %s\n", policy.PolicyID, policy.PolicyType, policy.Disposition)
+			fmt.Fprintf(w, "    %s\n", policy.Title)
 		}
 	}
 }
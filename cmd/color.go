@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/tylerbryy/verity-cli/pkg/output"
+)
+
+var colorMode string
+
+// useColor is decided by setupColor before any command runs.
+var useColor bool
+
+const (
+	ansiRed    = "31"
+	ansiGreen  = "32"
+	ansiYellow = "33"
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "Color table output (auto, always, never); auto honors NO_COLOR")
+	viper.BindPFlag("color", rootCmd.PersistentFlags().Lookup("color"))
+}
+
+// setupColor turns color on for --color=always, or for auto when output
+// goes to a terminal and neither NO_COLOR nor TERM=dumb asks otherwise.
+func setupColor() error {
+	switch mode := viper.GetString("color"); mode {
+	case "always":
+		useColor = true
+	case "never":
+		useColor = false
+	case "auto":
+		_, _, tty := terminalSize(os.Stdout)
+		useColor = tty && outputFile == "" && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+	default:
+		return usageErrorf("invalid --color %q (must be auto, always or never)", mode)
+	}
+	return nil
+}
+
+func paint(code, s string) string {
+	if !useColor {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+func green(s string) string  { return paint(ansiGreen, s) }
+func red(s string) string    { return paint(ansiRed, s) }
+func yellow(s string) string { return paint(ansiYellow, s) }
+
+// good colors s green when ok is true and red otherwise.
+func good(ok bool, s string) string {
+	if ok {
+		return green(s)
+	}
+	return red(s)
+}
+
+// toned colors s by tone.
+func toned(tone output.Tone, s string) string {
+	switch tone {
+	case output.ToneGood:
+		return green(s)
+	case output.ToneWarn:
+		return yellow(s)
+	case output.ToneBad:
+		return red(s)
+	}
+	return s
+}
+
+// paTone rates a prior-auth requirement: required is a warning, not an
+// error.
+func paTone(required bool) output.Tone {
+	if required {
+		return output.ToneWarn
+	}
+	return output.ToneGood
+}
+
+func paStatus(required bool) string {
+	return toned(paTone(required), strconv.FormatBool(required))
+}
+
+// statusTone rates the status strings used by health checks, research tasks
+// and webhook tests.
+func statusTone(s string) output.Tone {
+	switch strings.ToLower(s) {
+	case "ok", "healthy", "up", "pass", "passed", "success", "succeeded", "completed", "complete", "active", "delivered":
+		return output.ToneGood
+	case "degraded", "warn", "warning", "pending", "queued", "processing", "running", "in_progress":
+		return output.ToneWarn
+	case "":
+		return output.ToneNone
+	}
+	return output.ToneBad
+}
+
+func status(s string) string {
+	return toned(statusTone(s), s)
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tylerbryy/verity-cli/pkg/client"
+)
+
+// Process exit codes. Scripts may rely on these, so never renumber them.
+const (
+	ExitOK          = 0
+	ExitError       = 1
+	ExitUsage       = 2
+	ExitAuth        = 3
+	ExitNotFound    = 4
+	ExitRateLimited = 5
+	ExitServer      = 6
+	ExitNetwork     = 7
+	// ExitCondition is returned when an opt-in --fail-if-* check matches.
+	ExitCondition = 10
+	ExitCancelled = 130
+)
+
+var errMissingAPIKey = errors.New("API key is required. Set VERITY_API_KEY or use --api-key flag")
+
+// usageError marks a mistake in how the command was invoked.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+func (e *usageError) Unwrap() error { return e.err }
+
+func usageErrorf(format string, args ...interface{}) error {
+	return &usageError{err: fmt.Errorf(format, args...)}
+}
+
+// conditionError reports that a --fail-if-* check matched. The result has
+// already been printed, so it is not reported again.
+type conditionError struct {
+	reason string
+}
+
+func (e *conditionError) Error() string { return e.reason }
+
+// ExitCode maps an error returned by Execute to the process exit status.
+func ExitCode(err error) int {
+	var (
+		usageErr     *usageError
+		conditionErr *conditionError
+		apiErr       *client.APIError
+		urlErr       *url.Error
+		netErr       net.Error
+		pathErr      *fs.PathError
+	)
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrCancelled):
+		return ExitCancelled
+	case errors.As(err, &usageErr):
+		return ExitUsage
+	case errors.As(err, &conditionErr):
+		return ExitCondition
+	case errors.Is(err, errMissingAPIKey):
+		return ExitAuth
+	case errors.As(err, &apiErr):
+		switch {
+		case apiErr.StatusCode == http.StatusUnauthorized, apiErr.StatusCode == http.StatusForbidden:
+			return ExitAuth
+		case apiErr.StatusCode == http.StatusNotFound:
+			return ExitNotFound
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return ExitRateLimited
+		case apiErr.StatusCode >= 500:
+			return ExitServer
+		}
+		return ExitError
+	case errors.As(err, &urlErr):
+		return ExitNetwork
+	case errors.As(err, &pathErr):
+		// Local file errors, such as writing --output-file. Checked before
+		// net.Error because the syscall.Errno a *fs.PathError usually wraps
+		// has Timeout and Temporary methods, so errors.As would find one.
+		return ExitError
+	case errors.As(err, &netErr):
+		return ExitNetwork
+	}
+	return ExitError
+}
+
+// reportError writes a failed command's error to stderr. JSON output gets
+// the full error object in the API's envelope; table output gets the
+// message followed by the API's hint, when it sent one.
+func reportError(cmd *cobra.Command, err error) {
+	var conditionErr *conditionError
+	if errors.As(err, &conditionErr) {
+		return
+	}
+
+	var apiErr *client.APIError
+	isAPIErr := errors.As(err, &apiErr)
+
+	if getOutput() == "json" {
+		payload := map[string]interface{}{"success": false}
+		if isAPIErr {
+			payload["error"] = apiErr
+		} else {
+			payload["error"] = map[string]string{"message": err.Error()}
+		}
+		jsonData, _ := json.MarshalIndent(payload, "", "  ")
+		fmt.Fprintln(os.Stderr, string(jsonData))
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	if isAPIErr {
+		if apiErr.Hint != "" {
+			fmt.Fprintf(os.Stderr, "Hint: %s\n", apiErr.Hint)
+		}
+		if apiErr.RequestID != "" {
+			fmt.Fprintf(os.Stderr, "Request ID: %s\n", apiErr.RequestID)
+		}
+	}
+
+	var usageErr *usageError
+	if errors.As(err, &usageErr) && cmd != nil {
+		fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
+	}
+}
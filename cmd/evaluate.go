@@ -1,11 +1,12 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/spf13/cobra"
 	"github.com/tylerbryy/verity-cli/pkg/client"
+	"github.com/tylerbryy/verity-cli/pkg/output"
 )
 
 var evaluateCmd = &cobra.Command{
@@ -13,57 +14,57 @@ var evaluateCmd = &cobra.Command{
 	Short: "Evaluate coverage for a policy",
 	Long:  "Evaluate whether a procedure is covered under a specific policy given patient criteria",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		policyID := args[0]
-		c := client.New(getAPIKey(), getBaseURL())
-
-		reqBody := map[string]interface{}{
-			"policy_id": policyID,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
 		}
 
 		age, _ := cmd.Flags().GetInt("age")
-		if age > 0 {
-			reqBody["age"] = age
-		}
-
 		gender, _ := cmd.Flags().GetString("gender")
-		if gender != "" {
-			reqBody["gender"] = gender
-		}
-
 		diagnosis, _ := cmd.Flags().GetStringSlice("diagnosis")
-		if len(diagnosis) > 0 {
-			reqBody["diagnosis_codes"] = diagnosis
-		}
-
 		procedure, _ := cmd.Flags().GetString("procedure")
-		if procedure != "" {
-			reqBody["procedure_code"] = procedure
-		}
-
 		modifier, _ := cmd.Flags().GetString("modifier")
-		if modifier != "" {
-			reqBody["modifier"] = modifier
-		}
-
 		pos, _ := cmd.Flags().GetString("pos")
-		if pos != "" {
-			reqBody["place_of_service"] = pos
+
+		ctx, cancel := commandContext(cmd, defaultTimeout)
+		defer cancel()
+
+		result, err := c.EvaluateCoverage(ctx, client.EvaluateRequest{
+			PolicyID:       args[0],
+			Age:            age,
+			Gender:         gender,
+			DiagnosisCodes: diagnosis,
+			ProcedureCode:  procedure,
+			Modifier:       modifier,
+			PlaceOfService: pos,
+		})
+		if err != nil {
+			return err
 		}
 
-		var result map[string]interface{}
-		if err := c.Post("/coverage/evaluate", reqBody, &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
+		if err := render(&output.View{
+			Data: result,
+			Table: func(w io.Writer) {
+				printEvaluateResult(w, result.Data)
+			},
+			Document: func() *output.Document {
+				return evaluateDocument(args[0], result.Data)
+			},
+		}); err != nil {
+			return err
 		}
 
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printEvaluateResult(result)
+		failIfNotCovered, _ := cmd.Flags().GetBool("fail-if-not-covered")
+		if failIfNotCovered && result.Mismatch() != nil {
+			// Part of the result was dropped, so the check cannot be
+			// trusted either way.
+			return fmt.Errorf("cannot check --fail-if-not-covered: %w", result.Mismatch())
+		}
+		if failIfNotCovered && !result.Data.Covered {
+			return &conditionError{reason: "procedure is not covered"}
 		}
+		return nil
 	},
 }
 
@@ -76,51 +77,75 @@ func init() {
 	evaluateCmd.Flags().StringP("procedure", "p", "", "Procedure code (CPT/HCPCS)")
 	evaluateCmd.Flags().StringP("modifier", "m", "", "Procedure modifier")
 	evaluateCmd.Flags().String("pos", "", "Place of service code")
+	evaluateCmd.Flags().Bool("fail-if-not-covered", false, "Exit with status 10 if the procedure is not covered")
 }
 
-func printEvaluateResult(result map[string]interface{}) {
-	data, ok := result["data"].(map[string]interface{})
-	if !ok {
-		fmt.Println("Invalid response format")
-		return
-	}
-
-	covered := fmt.Sprintf("%v", data["covered"])
-	if covered == "true" {
-		fmt.Printf("Coverage: COVERED\n")
+func printEvaluateResult(w io.Writer, data client.EvaluateResult) {
+	if data.Covered {
+		fmt.Fprintf(w, "Coverage: %s\n", green("COVERED"))
 	} else {
-		fmt.Printf("Coverage: NOT COVERED\n")
+		fmt.Fprintf(w, "Coverage: %s\n", red("NOT COVERED"))
 	}
 
-	if confidence, ok := data["confidence"]; ok {
-		fmt.Printf("Confidence: %v\n", confidence)
+	if data.Confidence != "" {
+		fmt.Fprintf(w, "Confidence: %s\n", data.Confidence)
 	}
 
-	if reasons, ok := data["reasons"].([]interface{}); ok && len(reasons) > 0 {
-		fmt.Println("\nReasons:")
-		for _, reason := range reasons {
-			fmt.Printf("  - %v\n", reason)
+	if len(data.Reasons) > 0 {
+		fmt.Fprintln(w, "\nReasons:")
+		for _, reason := range data.Reasons {
+			fmt.Fprintf(w, "  - %s\n", reason)
 		}
 	}
 
-	if policyID, ok := data["policy_id"].(string); ok && policyID != "" {
-		fmt.Printf("\nPolicy: %s\n", policyID)
+	if data.PolicyID != "" {
+		fmt.Fprintf(w, "\nPolicy: %s\n", data.PolicyID)
 	}
 
-	if criteria, ok := data["matched_criteria"].([]interface{}); ok && len(criteria) > 0 {
-		fmt.Println("\nMatched Criteria:")
-		for _, c := range criteria {
-			if cMap, ok := c.(map[string]interface{}); ok {
-				if section, ok := cMap["section"].(string); ok {
-					fmt.Printf("  [%s] ", section)
-				}
-				if text, ok := cMap["text"].(string); ok {
-					if len(text) > 120 {
-						text = text[:117] + "..."
-					}
-					fmt.Printf("%s\n", text)
-				}
+	if len(data.MatchedCriteria) > 0 {
+		fmt.Fprintln(w, "\nMatched Criteria:")
+		for _, criteria := range data.MatchedCriteria {
+			if criteria.Section != "" {
+				fmt.Fprintf(w, "  [%s] ", criteria.Section)
 			}
+			fmt.Fprintf(w, "%s\n", output.Truncate(120, criteria.Text))
+		}
+	}
+}
+
+func evaluateDocument(policyID string, data client.EvaluateResult) *output.Document {
+	verdict, tone := "Not covered", output.ToneBad
+	if data.Covered {
+		verdict, tone = "Covered", output.ToneGood
+	}
+	if data.PolicyID != "" {
+		policyID = data.PolicyID
+	}
+
+	doc := &output.Document{Title: "Coverage Evaluation: " + policyID}
+	doc.Add(output.Fields{
+		{Label: "Coverage", Value: output.Plain(verdict), Tone: tone},
+		{Label: "Confidence", Value: output.Plain(data.Confidence)},
+		{Label: "Policy", Value: policyLink(policyID)},
+	})
+
+	if len(data.Reasons) > 0 {
+		list := output.List{}
+		for _, reason := range data.Reasons {
+			list = append(list, output.Plain(reason))
+		}
+		doc.Add(output.Heading{Level: 2, Text: "Reasons"}, list)
+	}
+
+	if len(data.MatchedCriteria) > 0 {
+		table := output.Table{Headers: []string{"Section", "Criteria"}}
+		for _, criteria := range data.MatchedCriteria {
+			table.Rows = append(table.Rows, []output.Text{
+				output.Plain(criteria.Section),
+				output.Plain(criteria.Text),
+			})
 		}
+		doc.Add(output.Heading{Level: 2, Text: "Matched Criteria"}, table)
 	}
+	return doc
 }
@@ -1,33 +1,39 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
 
 	"github.com/spf13/cobra"
 	"github.com/tylerbryy/verity-cli/pkg/client"
+	"github.com/tylerbryy/verity-cli/pkg/output"
 )
 
 var healthCmd = &cobra.Command{
 	Use:   "health",
 	Short: "Check API health status",
 	Long:  "Check the health status of the Verity API including database and Redis checks",
-	Run: func(cmd *cobra.Command, args []string) {
-		c := client.New(getAPIKey(), getBaseURL())
-
-		var result map[string]interface{}
-		if err := c.Get("/health", &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
 		}
 
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printHealthResult(result)
+		ctx, cancel := commandContext(cmd, healthTimeout)
+		defer cancel()
+
+		result, err := c.Health(ctx)
+		if err != nil {
+			return err
 		}
+
+		return render(&output.View{
+			Data: result,
+			Table: func(w io.Writer) {
+				printHealthResult(w, result.Data)
+			},
+		})
 	},
 }
 
@@ -35,23 +41,21 @@ func init() {
 	rootCmd.AddCommand(healthCmd)
 }
 
-func printHealthResult(result map[string]interface{}) {
-	data, ok := result["data"].(map[string]interface{})
-	if !ok {
-		fmt.Println("Invalid response format")
-		return
-	}
+func printHealthResult(w io.Writer, data client.HealthStatus) {
+	fmt.Fprintf(w, "Status: %s\n", status(data.Status))
+	fmt.Fprintf(w, "Version: %s\n", data.Version)
+	fmt.Fprintf(w, "Timestamp: %s\n", data.Timestamp)
 
-	fmt.Printf("Status: %v\n", data["status"])
-	fmt.Printf("Version: %v\n", data["version"])
-	fmt.Printf("Timestamp: %v\n", data["timestamp"])
+	if len(data.Checks) > 0 {
+		names := make([]string, 0, len(data.Checks))
+		for name := range data.Checks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
 
-	if checks, ok := data["checks"].(map[string]interface{}); ok {
-		fmt.Println("\nChecks:")
-		for name, check := range checks {
-			if checkMap, ok := check.(map[string]interface{}); ok {
-				fmt.Printf("  %s: %v\n", name, checkMap["status"])
-			}
+		fmt.Fprintln(w, "\nChecks:")
+		for _, name := range names {
+			fmt.Fprintf(w, "  %s: %s\n", name, status(data.Checks[name].Status))
 		}
 	}
 }
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tylerbryy/verity-cli/pkg/mock"
+)
+
+var (
+	mockAddr        string
+	mockFixturesDir string
+	mockLatency     time.Duration
+	mockErrorRate   float64
+	mockRateLimit   int
+	mockFailures    []string
+	mockRequireKey  string
+	mockQuiet       bool
+)
+
+var mockCmd = &cobra.Command{
+	Use:   "mock",
+	Short: "Run a local mock of the Verity API",
+	Long: `Run a local HTTP server implementing every endpoint the CLI calls, answering
+from built-in fixtures. Point --base-url at it to use the CLI offline or to
+script against predictable data.`,
+}
+
+var mockServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Start the mock API server",
+	Long: `Start the mock API server. It accepts any non-empty API key unless
+--require-key is given, and serves until interrupted.
+
+Files in --fixtures replace the built-in fixture of the same name; write the
+built-in set out with 'verity mock fixtures' to start from. Errors, latency
+and rate limiting can be simulated to exercise retries and error handling.`,
+	Example: `  verity mock serve
+  verity --base-url http://127.0.0.1:8787/api/v1 --api-key test policies list
+
+  verity mock serve --fixtures ./fixtures --latency 200ms
+  verity mock serve --rate-limit 10 --error-rate 0.2
+  verity mock serve --fail /prior-auth=500 --fail /policies/L33831=404`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if mockErrorRate < 0 || mockErrorRate > 1 {
+			return usageErrorf("--error-rate must be between 0 and 1")
+		}
+		if mockRateLimit < 0 {
+			return usageErrorf("--rate-limit must not be negative")
+		}
+		failures, err := parseMockFailures(mockFailures)
+		if err != nil {
+			return err
+		}
+
+		fixtures, err := mock.LoadFixtures(mockFixturesDir)
+		if err != nil {
+			return fmt.Errorf("loading fixtures: %w", err)
+		}
+
+		opts := mock.Options{
+			APIKey:    mockRequireKey,
+			Latency:   mockLatency,
+			ErrorRate: mockErrorRate,
+			RateLimit: mockRateLimit,
+			Failures:  failures,
+		}
+		if !mockQuiet {
+			opts.Logf = func(format string, args ...interface{}) {
+				fmt.Fprintf(os.Stderr, format+"\n", args...)
+			}
+		}
+
+		ln, err := net.Listen("tcp", mockAddr)
+		if err != nil {
+			return err
+		}
+		srv := &http.Server{Handler: mock.NewServer(fixtures, opts)}
+
+		url := "http://" + ln.Addr().String() + mock.BasePath
+		fmt.Fprintf(os.Stderr, "Mock Verity API listening on %s\n", url)
+		fmt.Fprintf(os.Stderr, "Try: verity --base-url %s --api-key test health\n", url)
+
+		errc := make(chan error, 1)
+		go func() {
+			errc <- srv.Serve(ln)
+		}()
+
+		select {
+		case err := <-errc:
+			return err
+		case <-cmd.Context().Done():
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+var mockFixturesCmd = &cobra.Command{
+	Use:   "fixtures <dir>",
+	Short: "Write the built-in fixtures to a directory",
+	Long: `Write the built-in fixtures to a directory as a starting point for
+'verity mock serve --fixtures'. Files that already exist are left alone.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		written, err := mock.WriteFixtures(args[0])
+		if err != nil {
+			return err
+		}
+		for _, name := range written {
+			fmt.Fprintln(os.Stderr, "Wrote "+name)
+		}
+		if len(written) == 0 {
+			fmt.Fprintln(os.Stderr, "All fixtures already exist in "+args[0])
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mockCmd)
+	mockCmd.AddCommand(mockServeCmd)
+	mockCmd.AddCommand(mockFixturesCmd)
+
+	mockServeCmd.Flags().StringVar(&mockAddr, "addr", "127.0.0.1:8787", "Address to listen on")
+	mockServeCmd.Flags().StringVar(&mockFixturesDir, "fixtures", "", "Directory of fixture files overriding the built-in ones")
+	mockServeCmd.Flags().DurationVar(&mockLatency, "latency", 0, "Delay every response by this long, e.g. 250ms")
+	mockServeCmd.Flags().Float64Var(&mockErrorRate, "error-rate", 0, "Fraction of requests (0-1) that fail with 503")
+	mockServeCmd.Flags().IntVar(&mockRateLimit, "rate-limit", 0, "Requests allowed per minute before answering 429 (0 is unlimited)")
+	mockServeCmd.Flags().StringArrayVar(&mockFailures, "fail", nil, "Fail requests under PATH with STATUS, as PATH=STATUS (repeatable)")
+	mockServeCmd.Flags().StringVar(&mockRequireKey, "require-key", "", "Accept only this API key instead of any key")
+	mockServeCmd.Flags().BoolVarP(&mockQuiet, "quiet", "q", false, "Do not log requests")
+}
+
+// parseMockFailures parses --fail values such as /policies=500.
+func parseMockFailures(values []string) (map[string]int, error) {
+	failures := map[string]int{}
+	for _, v := range values {
+		path, code, ok := strings.Cut(v, "=")
+		status, err := strconv.Atoi(code)
+		if !ok || err != nil || status < 400 || status > 599 || !strings.HasPrefix(path, "/") {
+			return nil, usageErrorf("invalid --fail %q: want PATH=STATUS, e.g. /policies=500", v)
+		}
+		failures[strings.TrimPrefix(path, mock.BasePath)] = status
+	}
+	return failures, nil
+}
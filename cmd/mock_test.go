@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tylerbryy/verity-cli/pkg/mock"
+)
+
+// The smoke tests run the CLI in a child process, since its flags and
+// configuration are package state that one run leaves behind for the next.
+// The child is this test binary with verityArgsEnv set; TestMain then runs
+// the CLI with those arguments instead of the tests.
+const verityArgsEnv = "VERITY_TEST_ARGS"
+
+func TestMain(m *testing.M) {
+	if args, ok := os.LookupEnv(verityArgsEnv); ok {
+		rootCmd.SetArgs(strings.Split(args, "\x1f"))
+		os.Exit(ExitCode(Execute()))
+	}
+	os.Exit(m.Run())
+}
+
+// verityCommand returns the CLI as a child process run with args, isolated
+// from the user's config, cache and environment.
+func verityCommand(t *testing.T, args ...string) *exec.Cmd {
+	t.Helper()
+	c := exec.Command(os.Args[0])
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "VERITY_") {
+			c.Env = append(c.Env, kv)
+		}
+	}
+	c.Env = append(c.Env,
+		verityArgsEnv+"="+strings.Join(args, "\x1f"),
+		"HOME="+t.TempDir(),
+		"XDG_CACHE_HOME="+t.TempDir(),
+		"NO_COLOR=1",
+	)
+	return c
+}
+
+// verity runs the CLI to completion and returns its output and exit code.
+func verity(t *testing.T, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	c := verityCommand(t, args...)
+	c.Stdout, c.Stderr = &out, &errOut
+	err := c.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		code = exitErr.ExitCode()
+	case err != nil:
+		t.Fatalf("running verity %s: %v", strings.Join(args, " "), err)
+	}
+	return out.String(), errOut.String(), code
+}
+
+// mockServer starts a mock API with the built-in fixtures, or the overrides
+// in fixturesDir, and returns its base URL.
+func mockServer(t *testing.T, fixturesDir string, opts mock.Options) string {
+	t.Helper()
+	fixtures, err := mock.LoadFixtures(fixturesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(mock.NewServer(fixtures, opts))
+	t.Cleanup(srv.Close)
+	return srv.URL + mock.BasePath
+}
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		opts mock.Options
+		// warmup requests are sent before the command, to use up a rate
+		// limit.
+		warmup int
+		args   []string
+		want   int
+		stderr string
+	}{
+		{"ok", mock.Options{}, 0, []string{"health"}, ExitOK, ""},
+		{"wrong key", mock.Options{APIKey: "secret"}, 0, []string{"health"}, ExitAuth, "Invalid or missing API key"},
+		{"not found", mock.Options{}, 0, []string{"policies", "get", "NOPE"}, ExitNotFound, "Policy NOPE not found"},
+		{"rate limited", mock.Options{RateLimit: 1}, 1, []string{"health"}, ExitRateLimited, "Rate limit exceeded"},
+		{"failed path", mock.Options{Failures: map[string]int{"/policies": 500}}, 0, []string{"policies", "list"}, ExitServer, "Simulated failure for /policies"},
+		{"failed path spares others", mock.Options{Failures: map[string]int{"/policies": 500}}, 0, []string{"jurisdictions"}, ExitOK, ""},
+		{"failed path as 429", mock.Options{Failures: map[string]int{"/health": 429}}, 0, []string{"health"}, ExitRateLimited, ""},
+		{"error rate", mock.Options{ErrorRate: 1}, 0, []string{"jurisdictions"}, ExitServer, "Simulated outage"},
+		{"usage", mock.Options{}, 0, []string{"policies", "get"}, ExitUsage, ""},
+		{"limit too large", mock.Options{}, 0, []string{"policies", "list", "--limit", "101"}, ExitUsage, "--limit must be between 1 and 100"},
+		{"required flags", mock.Options{}, 0, []string{"webhooks", "create"}, ExitUsage, `required flag(s) "events", "url" not set`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := mockServer(t, "", tt.opts)
+			for i := 0; i < tt.warmup; i++ {
+				req, _ := http.NewRequest("GET", url+"/health", nil)
+				req.Header.Set("Authorization", "Bearer test")
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				resp.Body.Close()
+			}
+
+			args := append([]string{"--base-url", url, "--api-key", "test", "--max-retries", "0"}, tt.args...)
+			_, stderr, code := verity(t, args...)
+			if code != tt.want {
+				t.Fatalf("exit code = %d, want %d; stderr:\n%s", code, tt.want, stderr)
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestFixtureOverride(t *testing.T) {
+	dir := t.TempDir()
+	override := `[{"jurisdiction_code": "ZZ", "mac_name": "Test MAC", "states": ["XX"]}]`
+	if err := os.WriteFile(filepath.Join(dir, "jurisdictions.json"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	url := mockServer(t, dir, mock.Options{})
+
+	stdout, stderr, code := verity(t, "--base-url", url, "--api-key", "test", "jurisdictions", "-o", "json", "--query", ".data[].jurisdiction_code")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d; stderr:\n%s", code, stderr)
+	}
+	if got := strings.TrimSpace(stdout); got != `"ZZ"` {
+		t.Errorf("jurisdiction codes = %s, want only the override's \"ZZ\"", got)
+	}
+}
+
+// TestFailIfMismatch checks that a --fail-if-* flag fails rather than
+// passes when the field it tests arrives with the wrong type. The mock's
+// fixtures are typed, so a stub server sends the bad responses.
+func TestFailIfMismatch(t *testing.T) {
+	responses := map[string]string{
+		"/prior-auth/check":  `{"success": true, "data": {"pa_required": "yes", "confidence": "high"}}`,
+		"/coverage/evaluate": `{"success": true, "data": {"covered": "yes", "policy_id": "L35036"}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(responses[r.URL.Path]))
+	}))
+	defer srv.Close()
+
+	for _, args := range [][]string{
+		{"prior-auth", "72148", "--fail-if-required"},
+		{"evaluate", "L35036", "--procedure", "72148", "--fail-if-not-covered"},
+	} {
+		t.Run(args[0], func(t *testing.T) {
+			_, stderr, code := verity(t, append([]string{"--base-url", srv.URL, "--api-key", "test"}, args...)...)
+			if code != ExitError {
+				t.Errorf("exit code = %d, want %d; stderr:\n%s", code, ExitError, stderr)
+			}
+			if !strings.Contains(stderr, "cannot check") {
+				t.Errorf("stderr = %q, want it to explain the check could not run", stderr)
+			}
+		})
+	}
+}
+
+// TestMockServe runs mock serve itself, with --fail and --fixtures, and
+// checks it shuts down cleanly on Ctrl-C.
+func TestMockServe(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sending os.Interrupt is not supported on Windows")
+	}
+
+	dir := t.TempDir()
+	override := `[{"jurisdiction_code": "ZZ", "mac_name": "Test MAC", "states": ["XX"]}]`
+	if err := os.WriteFile(filepath.Join(dir, "jurisdictions.json"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := verityCommand(t, "mock", "serve", "--addr", "127.0.0.1:0", "--quiet",
+		"--fixtures", dir, "--fail", "/api/v1/policies/L33831=404")
+	stderr, err := serve.StderrPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := serve.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer serve.Process.Kill()
+
+	lines := bufio.NewScanner(stderr)
+	var url string
+	for url == "" && lines.Scan() {
+		url, _ = strings.CutPrefix(lines.Text(), "Mock Verity API listening on ")
+	}
+	if url == "" {
+		t.Fatalf("mock serve did not report its address: %v", lines.Err())
+	}
+	// Keep reading, so the server never blocks on a full pipe. The read
+	// ends when the process exits.
+	exited := make(chan struct{})
+	go func() {
+		for lines.Scan() {
+		}
+		close(exited)
+	}()
+
+	base := []string{"--base-url", url, "--api-key", "test", "--max-retries", "0"}
+	if stdout, stderr, code := verity(t, append(base, "jurisdictions", "-o", "json")...); code != ExitOK || !strings.Contains(stdout, `"ZZ"`) {
+		t.Errorf("jurisdictions: exit %d, stdout %q, stderr %q; want the override", code, stdout, stderr)
+	}
+	if _, stderr, code := verity(t, append(base, "policies", "get", "L33831")...); code != ExitNotFound {
+		t.Errorf("policies get L33831: exit %d, want %d from --fail; stderr:\n%s", code, ExitNotFound, stderr)
+	}
+	if _, stderr, code := verity(t, append(base, "policies", "get", "L35036")...); code != ExitOK {
+		t.Errorf("policies get L35036: exit %d, want %d; stderr:\n%s", code, ExitOK, stderr)
+	}
+
+	if err := serve.Process.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-exited:
+	case <-time.After(10 * time.Second):
+		t.Fatal("mock serve did not stop after Ctrl-C")
+	}
+	if err := serve.Wait(); err != nil {
+		t.Errorf("mock serve after Ctrl-C: %v, want exit 0", err)
+	}
+}
+
+func TestMockServeUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{"--error-rate", "1.5"},
+		{"--rate-limit", "-1"},
+		{"--fail", "/policies"},
+		{"--fail", "policies=500"},
+		{"--fail", "/policies=200"},
+	} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			_, stderr, code := verity(t, append([]string{"mock", "serve", "--addr", "127.0.0.1:0"}, args...)...)
+			if code != ExitUsage {
+				t.Errorf("exit code = %d, want %d; stderr:\n%s", code, ExitUsage, stderr)
+			}
+		})
+	}
+}
+
+func TestParseMockFailures(t *testing.T) {
+	got, err := parseMockFailures([]string{"/policies=500", "/api/v1/prior-auth/check=429"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"/policies": 500, "/prior-auth/check": 429}
+	if len(got) != len(want) {
+		t.Fatalf("parseMockFailures = %v, want %v", got, want)
+	}
+	for path, status := range want {
+		if got[path] != status {
+			t.Errorf("parseMockFailures = %v, want %v", got, want)
+		}
+	}
+}
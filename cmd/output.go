@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/tylerbryy/verity-cli/pkg/client"
+	"github.com/tylerbryy/verity-cli/pkg/output"
+	"github.com/tylerbryy/verity-cli/pkg/query"
+)
+
+var (
+	templateText string
+	templateFile string
+	queryExpr    string
+	columns      []string
+	sortBy       string
+	wide         bool
+	noHeaders    bool
+	reportFile   string
+	outputFile   string
+)
+
+// activeQuery is the parsed --query expression, or nil.
+var activeQuery *query.Query
+
+// renderOptions is filled in by setupOutput before any command runs.
+var renderOptions = &output.Options{}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&templateText, "template", "", "Go template for -o template, e.g. '{{.code}}\\t{{.description}}'")
+	rootCmd.PersistentFlags().StringVar(&templateFile, "template-file", "", "File containing a Go template for -o template")
+	rootCmd.PersistentFlags().StringSliceVar(&columns, "columns", nil, "Table columns to show, by field name, e.g. policy_id,title")
+	rootCmd.PersistentFlags().StringVar(&sortBy, "sort-by", "", "Sort table rows by a field; prefix with - for descending, e.g. -total_paid")
+	rootCmd.PersistentFlags().BoolVar(&wide, "wide", false, "Show additional table columns")
+	rootCmd.PersistentFlags().BoolVar(&noHeaders, "no-headers", false, "Omit the table header row")
+	rootCmd.PersistentFlags().StringVarP(&outputFile, "output-file", "O", "", "Write output to this file instead of stdout; the format follows the extension unless -o is given")
+	rootCmd.PersistentFlags().StringVar(&reportFile, "report", "", "Also write an HTML report of the result to this file")
+	rootCmd.PersistentFlags().StringVarP(&queryExpr, "query", "Q", "", "jq-style expression applied to the response before output, e.g. '.data[].policy_id'")
+}
+
+// setupOutput validates the output flags and prepares renderOptions for
+// cmd.
+func setupOutput(cmd *cobra.Command, args []string) error {
+	format := getOutput()
+	if _, err := output.Lookup(format); err != nil {
+		return &usageError{err: err}
+	}
+
+	if format == "xlsx" && outputFile == "" {
+		if _, _, tty := terminalSize(os.Stdout); tty {
+			return usageErrorf("xlsx output is binary; use --output-file or redirect stdout")
+		}
+	}
+
+	if templateText != "" && templateFile != "" {
+		return usageErrorf("--template and --template-file are mutually exclusive")
+	}
+
+	text, name := templateText, "template"
+	// Allow \t and \n escapes so one-liners can be typed in the shell.
+	text = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(text)
+	if templateFile != "" {
+		data, err := os.ReadFile(templateFile)
+		if err != nil {
+			return usageErrorf("reading template: %v", err)
+		}
+		text, name = string(data), templateFile
+	}
+
+	if queryExpr != "" {
+		q, err := query.Parse(queryExpr)
+		if err != nil {
+			if cmd.Flags().Lookup("search") != nil && !strings.HasPrefix(strings.TrimSpace(queryExpr), ".") {
+				// policies list took its search text as --query before
+				// the flag became global, and search text rarely starts
+				// with a dot.
+				return usageErrorf("parsing query: %v (--query filters the response; search with --search)", err)
+			}
+			return usageErrorf("parsing query: %v", err)
+		}
+		activeQuery = q
+	}
+
+	renderOptions.Params = requestParams(cmd, args)
+	renderOptions.Columns = columns
+	renderOptions.SortBy = sortBy
+	renderOptions.Wide = wide
+	renderOptions.NoHeaders = noHeaders
+	if width, _, ok := terminalSize(os.Stdout); ok && outputFile == "" {
+		renderOptions.Width = width
+	}
+
+	if format == "template" && text == "" {
+		return usageErrorf("-o template requires --template or --template-file")
+	}
+	if text != "" {
+		tmpl, err := output.NewTemplate(name, text)
+		if err != nil {
+			return usageErrorf("parsing template: %v", err)
+		}
+		renderOptions.Template = tmpl
+	}
+	return nil
+}
+
+// render writes a command result to stdout in the --output format, after
+// applying --query.
+func render(v *output.View) error {
+	if activeQuery != nil {
+		queried, err := applyQuery(v)
+		if err != nil {
+			return err
+		}
+		v = queried
+	}
+
+	err := writeOutput(v)
+	if err == nil && reportFile != "" {
+		err = writeReport(v)
+	}
+
+	var unsupported *output.UnsupportedError
+	if errors.As(err, &unsupported) {
+		return &usageError{err: err}
+	}
+	return err
+}
+
+// writeOutput renders v to stdout, paged if it is long, or to
+// --output-file when set.
+func writeOutput(v *output.View) error {
+	if outputFile == "" {
+		return writeStdout(func(w io.Writer) error {
+			return output.Render(w, getOutput(), v, renderOptions)
+		})
+	}
+
+	err := writeFileAtomic(outputFile, func(w io.Writer) error {
+		return output.Render(w, getOutput(), v, renderOptions)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", outputFile)
+	return nil
+}
+
+// writeReport saves v as an HTML report for --report.
+func writeReport(v *output.View) error {
+	err := writeFileAtomic(reportFile, func(w io.Writer) error {
+		return output.Render(w, "html", v, renderOptions)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Report written to %s\n", reportFile)
+	return nil
+}
+
+// writeFileAtomic writes name through a temporary file in the same
+// directory and renames it into place, so a failed or interrupted run never
+// leaves a truncated file behind.
+func writeFileAtomic(name string, write func(w io.Writer) error) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if err := write(tmp); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	// CreateTemp makes the file private; give it the usual permissions.
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
+
+// requestParams describes what was asked for, for report footers: cmd's
+// arguments and every one of its own flags with a value. Global flags such
+// as --api-key are never included.
+func requestParams(cmd *cobra.Command, args []string) output.Fields {
+	var params output.Fields
+	if len(args) > 0 {
+		params = append(params, output.Field{Label: argsLabel(cmd), Value: output.Plain(strings.Join(args, ", "))})
+	}
+
+	cmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Name == "help" {
+			return
+		}
+		value := f.Value.String()
+		if s, ok := f.Value.(pflag.SliceValue); ok {
+			value = strings.Join(s.GetSlice(), ", ")
+		}
+		switch value {
+		case "", "0", "false":
+			return
+		}
+		params = append(params, output.Field{Label: "--" + f.Name, Value: output.Plain(value)})
+	})
+	return params
+}
+
+// argsLabel names cmd's positional arguments from its usage line, such as
+// "procedure-codes" for "prior-auth [procedure-codes...]".
+func argsLabel(cmd *cobra.Command) string {
+	_, rest, ok := strings.Cut(cmd.Use, "[")
+	if !ok {
+		return "arguments"
+	}
+	name, _, _ := strings.Cut(rest, "]")
+	return strings.TrimSuffix(name, "...")
+}
+
+// applyQuery runs --query against the full response, envelope included, and
+// returns a view of the result. Null results count as no match.
+func applyQuery(v *output.View) (*output.View, error) {
+	data, err := output.Normalize(v.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := activeQuery.Run(data)
+	if err != nil {
+		return nil, fmt.Errorf("query %q: %w", activeQuery, err)
+	}
+
+	var matches []interface{}
+	for _, r := range results {
+		if r != nil {
+			matches = append(matches, r)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("query %q matched nothing", activeQuery)
+	}
+
+	var value interface{} = matches
+	if len(matches) == 1 {
+		value = matches[0]
+	}
+
+	records, ok := value.([]interface{})
+	if !ok {
+		records = []interface{}{value}
+	}
+	return &output.View{Data: value, Records: records}, nil
+}
+
+// policyLink links a policy ID to its public text when the URL is known.
+func policyLink(policyID string) output.Text {
+	return output.Link(policyID, client.PolicyURL(policyID))
+}
+
+// yesNo spells out a boolean for report formats.
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
+
+// sourceList lists research sources, linking those that are URLs.
+func sourceList(sources []string) output.List {
+	list := output.List{}
+	for _, src := range sources {
+		if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+			list = append(list, output.Link(src, src))
+		} else {
+			list = append(list, output.Plain(src))
+		}
+	}
+	return list
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+)
+
+const defaultPager = "less -R"
+
+var noPager bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noPager, "no-pager", false, "Never pipe long output through a pager")
+	viper.BindPFlag("no_pager", rootCmd.PersistentFlags().Lookup("no-pager"))
+}
+
+// pagerCommand returns $VERITY_PAGER (or the pager config key), then
+// $PAGER, then less -R.
+func pagerCommand() string {
+	if p := viper.GetString("pager"); p != "" {
+		return p
+	}
+	if p := os.Getenv("PAGER"); p != "" {
+		return p
+	}
+	return defaultPager
+}
+
+// writeStdout runs write against stdout. When stdout is a terminal and the
+// output is taller than it, the output goes through the pager instead.
+func writeStdout(write func(w io.Writer) error) error {
+	width, height, tty := terminalSize(os.Stdout)
+	pager := pagerCommand()
+	if !tty || viper.GetBool("no_pager") || pager == "cat" {
+		return write(os.Stdout)
+	}
+
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		return err
+	}
+	if countLines(buf.String(), width) < height || !runPager(pager, buf.Bytes()) {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return nil
+}
+
+// runPager shows data through pager, reporting false if it could not be
+// started.
+func runPager(pager string, data []byte) bool {
+	args := strings.Fields(pager)
+	if len(args) == 0 {
+		return false
+	}
+
+	c := exec.Command(args[0], args[1:]...)
+	c.Stdin = bytes.NewReader(data)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Start(); err != nil {
+		return false
+	}
+
+	// The pager owns the terminal now; Ctrl-C is for it, not for us.
+	signal.Ignore(os.Interrupt)
+	c.Wait()
+	return true
+}
+
+// sgrSequence matches the ANSI color escapes written by --color, which take
+// up no columns on screen.
+var sgrSequence = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// countLines counts the terminal rows s takes up, including lines that
+// wrap at width.
+func countLines(s string, width int) int {
+	n := 0
+	for _, line := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+		runes := utf8.RuneCountInString(sgrSequence.ReplaceAllString(line, ""))
+		if width <= 0 || runes <= width {
+			n++
+			continue
+		}
+		n += (runes + width - 1) / width
+	}
+	return n
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import "testing"
+
+func TestCountLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  int
+	}{
+		{"single line", "hello\n", 80, 1},
+		{"no trailing newline", "a\nb", 80, 2},
+		{"wraps", "abcdefghij\n", 4, 3},
+		{"exact width", "abcd\n", 4, 1},
+		{"unknown width", "abcdefghij\n", 0, 1},
+		{"multibyte runes count once", "ééééé\n", 5, 1},
+		{"color escapes take no columns", "\x1b[32mabcd\x1b[0m\n", 4, 1},
+		{"bold and color", "\x1b[1;31mabcd\x1b[0m efgh\n", 9, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countLines(tt.s, tt.width); got != tt.want {
+				t.Errorf("countLines(%q, %d) = %d, want %d", tt.s, tt.width, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tylerbryy/verity-cli/pkg/client"
+	"github.com/tylerbryy/verity-cli/pkg/output"
+)
+
+// maxLimit is the largest page size the API accepts.
+const maxLimit = 100
+
+// maxPages caps --all so a huge result set, or a server that never stops
+// paginating, cannot keep the CLI fetching forever.
+const maxPages = 100
+
+// addPageFlags gives a list command --limit, --page and --all.
+func addPageFlags(cmd *cobra.Command) {
+	cmd.Flags().IntP("limit", "l", 50, fmt.Sprintf("Results per page (1-%d)", maxLimit))
+	cmd.Flags().Int("page", 0, "Page to fetch, starting at 1")
+	cmd.Flags().Bool("all", false, fmt.Sprintf("Fetch every page, up to %d", maxPages))
+}
+
+// pageFlags returns the values of the flags added by addPageFlags.
+func pageFlags(cmd *cobra.Command) (limit, page int, err error) {
+	limit, _ = cmd.Flags().GetInt("limit")
+	page, _ = cmd.Flags().GetInt("page")
+	if limit < 1 || limit > maxLimit {
+		return 0, 0, usageErrorf("--limit must be between 1 and %d", maxLimit)
+	}
+	if page < 0 {
+		return 0, 0, usageErrorf("--page must be at least 1")
+	}
+	return limit, page, nil
+}
+
+// renderPages renders the first page from pager or, with --all, every page.
+// ndjson streams each page as it arrives; other formats, --query and
+// --report need the whole result, so the pages are collected first. The
+// request timeout applies to each page, not to the whole walk.
+func renderPages[T any](cmd *cobra.Command, pager *client.Pager[T], view func(*client.Response[[]T]) *output.View) error {
+	all, _ := cmd.Flags().GetBool("all")
+	walk := &pageWalk[T]{cmd: cmd, pager: pager, progress: all}
+	if !walk.next() {
+		return pager.Err()
+	}
+	first := pager.Page()
+
+	if !all {
+		if pager.More() && getOutput() == "table" {
+			fmt.Fprintln(os.Stderr, "More results are available; use --page or --all to see them.")
+		}
+		return render(view(first))
+	}
+
+	if getOutput() == "ndjson" && activeQuery == nil && reportFile == "" {
+		v := view(first)
+		v.Next = func() (interface{}, error) {
+			if !walk.next() {
+				return nil, pager.Err()
+			}
+			return pageRecords(pager.Page()), nil
+		}
+		return render(v)
+	}
+
+	pages := []*client.Response[[]T]{first}
+	for walk.next() {
+		pages = append(pages, pager.Page())
+	}
+	if err := pager.Err(); err != nil {
+		return err
+	}
+	return render(view(client.JoinPages(pages)))
+}
+
+// pageRecords returns a page's records for ndjson, as the server sent them
+// when they are available.
+func pageRecords[T any](page *client.Response[[]T]) interface{} {
+	if records, ok := page.RawRecords(); ok {
+		return records
+	}
+	return page.Data
+}
+
+// pageWalk advances a pager for --all, reporting progress on stderr and
+// stopping at maxPages.
+type pageWalk[T any] struct {
+	cmd      *cobra.Command
+	pager    *client.Pager[T]
+	progress bool
+	records  int
+}
+
+func (w *pageWalk[T]) next() bool {
+	if w.pager.Pages() >= maxPages {
+		if w.pager.More() {
+			fmt.Fprintf(os.Stderr, "Warning: stopped after %d pages; the results are incomplete. Narrow the search or use --page to continue.\n", maxPages)
+		}
+		return false
+	}
+	ctx, cancel := commandContext(w.cmd, defaultTimeout)
+	defer cancel()
+	if !w.pager.Next(ctx) {
+		return false
+	}
+
+	w.records += len(w.pager.Page().Data)
+	if !w.progress || (w.pager.Pages() == 1 && !w.pager.More()) {
+		// A single page needs no progress report.
+		return true
+	}
+	info := w.pager.Info()
+	page := info.Page
+	if page == 0 {
+		page = w.pager.Pages()
+	}
+	if info.Total > 0 {
+		fmt.Fprintf(os.Stderr, "Fetched page %d (%d of %d records)\n", page, w.records, info.Total)
+	} else {
+		fmt.Fprintf(os.Stderr, "Fetched page %d (%d records)\n", page, w.records)
+	}
+	return true
+}
@@ -1,11 +1,13 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/tylerbryy/verity-cli/pkg/client"
+	"github.com/tylerbryy/verity-cli/pkg/output"
 )
 
 var policiesCmd = &cobra.Command{
@@ -17,50 +19,41 @@ var policiesCmd = &cobra.Command{
 var policiesListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "Search and list policies",
-	Run: func(cmd *cobra.Command, args []string) {
-		c := client.New(getAPIKey(), getBaseURL())
-
-		path := "/policies?limit=50"
-
-		query, _ := cmd.Flags().GetString("query")
-		if query != "" {
-			path += "&q=" + query
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
 		}
 
+		search, _ := cmd.Flags().GetString("search")
 		mode, _ := cmd.Flags().GetString("mode")
-		path += "&mode=" + mode
-
 		policyType, _ := cmd.Flags().GetString("type")
-		if policyType != "" {
-			path += "&policy_type=" + policyType
-		}
-
 		jurisdiction, _ := cmd.Flags().GetString("jurisdiction")
-		if jurisdiction != "" {
-			path += "&jurisdiction=" + jurisdiction
-		}
-
 		status, _ := cmd.Flags().GetString("status")
-		path += "&status=" + status
-
 		icd10, _ := cmd.Flags().GetString("icd10")
-		if icd10 != "" {
-			path += "&icd10=" + icd10
+		limit, page, err := pageFlags(cmd)
+		if err != nil {
+			return err
 		}
 
-		var result map[string]interface{}
-		if err := c.Get(path, &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
-		}
-
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printPoliciesList(result)
-		}
+		pager := c.ListPoliciesPages(&client.ListPoliciesOptions{
+			Query:        search,
+			Mode:         mode,
+			PolicyType:   policyType,
+			Jurisdiction: jurisdiction,
+			Status:       status,
+			ICD10:        icd10,
+			Limit:        limit,
+			Page:         page,
+		})
+		return renderPages(cmd, pager, func(result *client.Response[[]client.Policy]) *output.View {
+			return &output.View{
+				Data:    result,
+				Records: result.Data,
+				Columns: policyColumns,
+				Empty:   "No policies found",
+			}
+		})
 	},
 }
 
@@ -68,37 +61,31 @@ var policiesGetCmd = &cobra.Command{
 	Use:   "get [policy-id]",
 	Short: "Get policy details",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		policyID := args[0]
-		c := client.New(getAPIKey(), getBaseURL())
-
-		path := fmt.Sprintf("/policies/%s", policyID)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
 
 		include, _ := cmd.Flags().GetStringSlice("include")
-		if len(include) > 0 {
-			includeStr := ""
-			for i, inc := range include {
-				if i > 0 {
-					includeStr += ","
-				}
-				includeStr += inc
-			}
-			path += "?include=" + includeStr
-		}
 
-		var result map[string]interface{}
-		if err := c.Get(path, &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
-		}
+		ctx, cancel := commandContext(cmd, defaultTimeout)
+		defer cancel()
 
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printPolicyDetail(result)
+		result, err := c.GetPolicy(ctx, args[0], &client.GetPolicyOptions{Include: include})
+		if err != nil {
+			return err
 		}
+
+		return render(&output.View{
+			Data: result,
+			Table: func(w io.Writer) {
+				printPolicyDetail(w, result.Data)
+			},
+			Document: func() *output.Document {
+				return policyDocument(result.Data)
+			},
+		})
 	},
 }
 
@@ -106,39 +93,35 @@ var policiesChangesCmd = &cobra.Command{
 	Use:   "changes",
 	Short: "Get policy change feed",
 	Long:  "Track changes across all policies - new, updated, or retired",
-	Run: func(cmd *cobra.Command, args []string) {
-		c := client.New(getAPIKey(), getBaseURL())
-
-		path := "/policies/changes?limit=50"
-
-		since, _ := cmd.Flags().GetString("since")
-		if since != "" {
-			path += "&since=" + since
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
 		}
 
+		since, _ := cmd.Flags().GetString("since")
 		policyID, _ := cmd.Flags().GetString("policy-id")
-		if policyID != "" {
-			path += "&policy_id=" + policyID
-		}
-
 		changeType, _ := cmd.Flags().GetString("change-type")
-		if changeType != "" {
-			path += "&change_type=" + changeType
-		}
-
-		var result map[string]interface{}
-		if err := c.Get(path, &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
+		limit, page, err := pageFlags(cmd)
+		if err != nil {
+			return err
 		}
 
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printPolicyChanges(result)
-		}
+		pager := c.ListPolicyChangesPages(&client.PolicyChangesOptions{
+			Since:      since,
+			PolicyID:   policyID,
+			ChangeType: changeType,
+			Limit:      limit,
+			Page:       page,
+		})
+		return renderPages(cmd, pager, func(result *client.Response[[]client.PolicyChange]) *output.View {
+			return &output.View{
+				Data:    result,
+				Records: result.Data,
+				Columns: policyChangeColumns,
+				Empty:   "No policy changes found",
+			}
+		})
 	},
 }
 
@@ -147,36 +130,39 @@ var policiesCompareCmd = &cobra.Command{
 	Short: "Compare policies across jurisdictions",
 	Long:  "Compare coverage policies for procedures across MAC jurisdictions",
 	Args:  cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		c := client.New(getAPIKey(), getBaseURL())
-
-		reqBody := map[string]interface{}{
-			"procedure_codes": args,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
 		}
 
 		policyType, _ := cmd.Flags().GetString("type")
-		if policyType != "" {
-			reqBody["policy_type"] = policyType
-		}
-
 		jurisdictions, _ := cmd.Flags().GetStringSlice("jurisdictions")
-		if len(jurisdictions) > 0 {
-			reqBody["jurisdictions"] = jurisdictions
-		}
 
-		var result map[string]interface{}
-		if err := c.Post("/policies/compare", reqBody, &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
-		}
+		ctx, cancel := commandContext(cmd, defaultTimeout)
+		defer cancel()
 
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printPolicyComparison(result)
+		result, err := c.ComparePolicies(ctx, client.ComparePoliciesRequest{
+			ProcedureCodes: args,
+			PolicyType:     policyType,
+			Jurisdictions:  jurisdictions,
+		})
+		if err != nil {
+			return err
 		}
+
+		return render(&output.View{
+			Data: result,
+			Table: func(w io.Writer) {
+				printPolicyComparison(w, result.Data)
+			},
+			Document: func() *output.Document {
+				return comparisonDocument(args, result.Data)
+			},
+			Sheets: func() []output.Sheet {
+				return comparisonSheets(result.Data)
+			},
+		})
 	},
 }
 
@@ -187,114 +173,191 @@ func init() {
 	policiesCmd.AddCommand(policiesChangesCmd)
 	policiesCmd.AddCommand(policiesCompareCmd)
 
-	policiesListCmd.Flags().StringP("query", "q", "", "Search query")
+	policiesListCmd.Flags().StringP("search", "q", "", "Search query")
 	policiesListCmd.Flags().StringP("mode", "m", "keyword", "Search mode (keyword, semantic)")
 	policiesListCmd.Flags().StringP("type", "t", "", "Policy type (LCD, Article, NCD)")
 	policiesListCmd.Flags().StringP("jurisdiction", "j", "", "MAC jurisdiction")
 	policiesListCmd.Flags().StringP("status", "s", "active", "Status (active, retired, all)")
 	policiesListCmd.Flags().String("icd10", "", "Filter by ICD-10 diagnosis code")
+	addPageFlags(policiesListCmd)
 
 	policiesGetCmd.Flags().StringSliceP("include", "i", []string{}, "Include additional data (criteria, codes, attachments, versions)")
 
 	policiesChangesCmd.Flags().String("since", "", "ISO8601 timestamp - only show changes after this date")
 	policiesChangesCmd.Flags().String("policy-id", "", "Filter to a specific policy")
 	policiesChangesCmd.Flags().String("change-type", "", "Filter by change type (created, updated, retired)")
+	addPageFlags(policiesChangesCmd)
 
 	policiesCompareCmd.Flags().StringP("type", "t", "", "Policy type (LCD, Article, NCD)")
 	policiesCompareCmd.Flags().StringSliceP("jurisdictions", "j", []string{}, "Specific jurisdictions to compare")
 }
 
-func printPoliciesList(result map[string]interface{}) {
-	data, ok := result["data"].([]interface{})
-	if !ok || len(data) == 0 {
-		fmt.Println("No policies found")
-		return
-	}
+var policyColumns = []output.Column{
+	{Field: "policy_id", Header: "ID", Link: client.PolicyURL},
+	{Field: "policy_type", Header: "TYPE"},
+	{Field: "jurisdiction"},
+	{Field: "status"},
+	{Field: "title"},
+	{Field: "effective_date", Header: "EFFECTIVE", Wide: true},
+	{Field: "disposition", Wide: true},
+}
 
-	fmt.Printf("Found %d policies:\n\n", len(data))
-	for _, p := range data {
-		policy := p.(map[string]interface{})
-		fmt.Printf("ID: %v\n", policy["policy_id"])
-		fmt.Printf("Title: %v\n", policy["title"])
-		fmt.Printf("Type: %v\n", policy["policy_type"])
-		if juris, ok := policy["jurisdiction"].(string); ok && juris != "" {
-			fmt.Printf("Jurisdiction: %s\n", juris)
-		}
-		fmt.Printf("Status: %v\n", policy["status"])
-		fmt.Println("---")
+func printPolicyDetail(w io.Writer, data client.Policy) {
+	fmt.Fprintf(w, "Policy ID: %s\n", data.PolicyID)
+	fmt.Fprintf(w, "Title: %s\n", data.Title)
+	fmt.Fprintf(w, "Type: %s\n", data.PolicyType)
+	fmt.Fprintf(w, "Status: %s\n", data.Status)
+
+	if data.Jurisdiction != "" {
+		fmt.Fprintf(w, "Jurisdiction: %s\n", data.Jurisdiction)
 	}
-}
 
-func printPolicyDetail(result map[string]interface{}) {
-	data, ok := result["data"].(map[string]interface{})
-	if !ok {
-		fmt.Println("Invalid response format")
-		return
+	if data.EffectiveDate != "" {
+		fmt.Fprintf(w, "Effective Date: %s\n", data.EffectiveDate)
 	}
 
-	fmt.Printf("Policy ID: %v\n", data["policy_id"])
-	fmt.Printf("Title: %v\n", data["title"])
-	fmt.Printf("Type: %v\n", data["policy_type"])
-	fmt.Printf("Status: %v\n", data["status"])
+	if data.Description != "" {
+		fmt.Fprintf(w, "\nDescription:\n%s\n", data.Description)
+	}
 
-	if juris, ok := data["jurisdiction"].(string); ok && juris != "" {
-		fmt.Printf("Jurisdiction: %s\n", juris)
+	if data.Summary != "" {
+		fmt.Fprintf(w, "\nSummary:\n%s\n", data.Summary)
 	}
+}
 
-	if date, ok := data["effective_date"].(string); ok && date != "" {
-		fmt.Printf("Effective Date: %s\n", date)
+var policyChangeColumns = []output.Column{
+	{Field: "policy_id", Header: "POLICY", Link: client.PolicyURL},
+	{Field: "change_type", Header: "TYPE"},
+	{Field: "timestamp", Header: "DATE"},
+	{Field: "change_summary", Header: "SUMMARY"},
+}
+
+func printPolicyComparison(w io.Writer, data client.PolicyComparison) {
+	for _, comp := range data.Comparison {
+		fmt.Fprintf(w, "Jurisdiction: %s (%s)\n", comp.Jurisdiction, comp.MacName)
+		fmt.Fprintf(w, "  Policies: %d\n", len(comp.Policies))
+		for _, policy := range comp.Policies {
+			fmt.Fprintf(w, "    - %s: %s (%s)\n", policy.PolicyID, policy.Title, policy.Disposition)
+		}
+		fmt.Fprintln(w, "---")
 	}
+}
 
-	if desc, ok := data["description"].(string); ok && desc != "" {
-		fmt.Printf("\nDescription:\n%s\n", desc)
+func policyDocument(data client.Policy) *output.Document {
+	doc := &output.Document{Title: data.PolicyID + ": " + data.Title}
+	doc.Add(output.Fields{
+		{Label: "Policy ID", Value: policyLink(data.PolicyID)},
+		{Label: "Type", Value: output.Plain(data.PolicyType)},
+		{Label: "Status", Value: output.Plain(data.Status)},
+		{Label: "Jurisdiction", Value: output.Plain(data.Jurisdiction)},
+		{Label: "Disposition", Value: output.Plain(data.Disposition)},
+		{Label: "Effective Date", Value: output.Plain(data.EffectiveDate)},
+	})
+
+	if data.Description != "" {
+		doc.Add(output.Heading{Level: 2, Text: "Description"}, output.Paragraph{Text: output.Plain(data.Description)})
+	}
+	if data.Summary != "" {
+		doc.Add(output.Heading{Level: 2, Text: "Summary"}, output.Paragraph{Text: output.Plain(data.Summary)})
 	}
 
-	if summary, ok := data["summary"].(string); ok && summary != "" {
-		fmt.Printf("\nSummary:\n%s\n", summary)
+	if len(data.Criteria) > 0 {
+		doc.Add(output.Heading{Level: 2, Text: "Coverage Criteria"})
+		for _, criteria := range data.Criteria {
+			if criteria.Section != "" {
+				doc.Add(output.Heading{Level: 3, Text: criteria.Section})
+			}
+			doc.Add(output.Paragraph{Text: output.Plain(criteria.Text)})
+		}
 	}
-}
 
-func printPolicyChanges(result map[string]interface{}) {
-	data, ok := result["data"].([]interface{})
-	if !ok || len(data) == 0 {
-		fmt.Println("No policy changes found")
-		return
+	if len(data.Codes) > 0 {
+		table := output.Table{Headers: []string{"Code", "System", "Description", "Disposition"}}
+		for _, code := range data.Codes {
+			table.Rows = append(table.Rows, []output.Text{
+				output.Plain(code.Code),
+				output.Plain(code.CodeSystem),
+				output.Plain(code.Description),
+				output.Plain(code.Disposition),
+			})
+		}
+		doc.Add(output.Heading{Level: 2, Text: "Codes"}, table)
 	}
 
-	fmt.Printf("Found %d changes:\n\n", len(data))
-	for _, c := range data {
-		change := c.(map[string]interface{})
-		fmt.Printf("Policy: %v\n", change["policy_id"])
-		fmt.Printf("Type: %v\n", change["change_type"])
-		if summary, ok := change["change_summary"].(string); ok && summary != "" {
-			fmt.Printf("Summary: %s\n", summary)
+	if len(data.Attachments) > 0 {
+		list := output.List{}
+		for _, att := range data.Attachments {
+			title := att.Title
+			if title == "" {
+				title = att.URL
+			}
+			list = append(list, output.Link(title, att.URL))
 		}
-		if ts, ok := change["timestamp"].(string); ok && ts != "" {
-			fmt.Printf("Date: %s\n", ts)
+		doc.Add(output.Heading{Level: 2, Text: "Attachments"}, list)
+	}
+
+	if len(data.Versions) > 0 {
+		table := output.Table{Headers: []string{"Version", "Effective", "Retired", "Summary"}}
+		for _, v := range data.Versions {
+			table.Rows = append(table.Rows, []output.Text{
+				output.Plain(v.Version),
+				output.Plain(v.EffectiveDate),
+				output.Plain(v.RetiredDate),
+				output.Plain(v.Summary),
+			})
 		}
-		fmt.Println("---")
+		doc.Add(output.Heading{Level: 2, Text: "Versions"}, table)
 	}
+	return doc
 }
 
-func printPolicyComparison(result map[string]interface{}) {
-	data, ok := result["data"].(map[string]interface{})
-	if !ok {
-		fmt.Println("Invalid response format")
-		return
+func comparisonDocument(codes []string, data client.PolicyComparison) *output.Document {
+	doc := &output.Document{Title: "Policy Comparison: " + strings.Join(codes, ", ")}
+	for _, comp := range data.Comparison {
+		heading := comp.Jurisdiction
+		if comp.MacName != "" {
+			heading += " (" + comp.MacName + ")"
+		}
+		doc.Add(output.Heading{Level: 2, Text: heading})
+
+		if len(comp.Policies) == 0 {
+			doc.Add(output.Paragraph{Text: output.Plain("No policies")})
+			continue
+		}
+		table := output.Table{Headers: []string{"Policy", "Title", "Type", "Disposition"}}
+		for _, policy := range comp.Policies {
+			table.Rows = append(table.Rows, []output.Text{
+				policyLink(policy.PolicyID),
+				output.Plain(policy.Title),
+				output.Plain(policy.PolicyType),
+				output.Plain(policy.Disposition),
+			})
+		}
+		doc.Add(table)
 	}
+	return doc
+}
 
-	if comparison, ok := data["comparison"].([]interface{}); ok {
-		for _, c := range comparison {
-			comp := c.(map[string]interface{})
-			fmt.Printf("Jurisdiction: %v (%v)\n", comp["jurisdiction"], comp["mac_name"])
-			if policies, ok := comp["policies"].([]interface{}); ok {
-				fmt.Printf("  Policies: %d\n", len(policies))
-				for _, p := range policies {
-					policy := p.(map[string]interface{})
-					fmt.Printf("    - %v: %v (%v)\n", policy["policy_id"], policy["title"], policy["disposition"])
-				}
-			}
-			fmt.Println("---")
+// comparisonSheets gives each jurisdiction its own sheet of policies.
+func comparisonSheets(data client.PolicyComparison) []output.Sheet {
+	sheets := make([]output.Sheet, 0, len(data.Comparison))
+	for _, comp := range data.Comparison {
+		name := comp.Jurisdiction
+		if comp.MacName != "" {
+			name += " - " + comp.MacName
 		}
+		sheets = append(sheets, output.Sheet{
+			Name:    name,
+			Records: comp.Policies,
+			Columns: []output.Column{
+				{Field: "policy_id", Header: "Policy ID"},
+				{Field: "title", Header: "Title"},
+				{Field: "policy_type", Header: "Type"},
+				{Field: "status", Header: "Status"},
+				{Field: "disposition", Header: "Disposition"},
+				{Field: "effective_date", Header: "Effective Date"},
+			},
+		})
 	}
+	return sheets
 }
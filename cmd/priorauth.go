@@ -1,11 +1,13 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/tylerbryy/verity-cli/pkg/client"
+	"github.com/tylerbryy/verity-cli/pkg/output"
 )
 
 var priorAuthCmd = &cobra.Command{
@@ -13,41 +15,52 @@ var priorAuthCmd = &cobra.Command{
 	Short: "Check prior authorization requirements",
 	Long:  "Check if procedures require prior authorization based on codes and state",
 	Args:  cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		c := client.New(getAPIKey(), getBaseURL())
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
 
 		diagnosisCodes, _ := cmd.Flags().GetStringSlice("diagnosis")
 		state, _ := cmd.Flags().GetString("state")
 		payer, _ := cmd.Flags().GetString("payer")
 
-		reqBody := map[string]interface{}{
-			"procedure_codes":    args,
-			"payer":              payer,
-			"criteria_page":      1,
-			"criteria_per_page":  25,
-		}
-
-		if len(diagnosisCodes) > 0 {
-			reqBody["diagnosis_codes"] = diagnosisCodes
+		ctx, cancel := commandContext(cmd, defaultTimeout)
+		defer cancel()
+
+		result, err := c.CheckPriorAuth(ctx, client.PriorAuthRequest{
+			ProcedureCodes:  args,
+			DiagnosisCodes:  diagnosisCodes,
+			State:           state,
+			Payer:           payer,
+			CriteriaPage:    1,
+			CriteriaPerPage: 25,
+		})
+		if err != nil {
+			return err
 		}
 
-		if state != "" {
-			reqBody["state"] = state
+		if err := render(&output.View{
+			Data: result,
+			Table: func(w io.Writer) {
+				printPriorAuthResult(w, result.Data)
+			},
+			Document: func() *output.Document {
+				return priorAuthDocument(args, result.Data)
+			},
+		}); err != nil {
+			return err
 		}
 
-		var result map[string]interface{}
-		if err := c.Post("/prior-auth/check", reqBody, &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
+		failIfRequired, _ := cmd.Flags().GetBool("fail-if-required")
+		if failIfRequired && result.Mismatch() != nil {
+			// A mistyped pa_required decodes as false, which would pass.
+			return fmt.Errorf("cannot check --fail-if-required: %w", result.Mismatch())
 		}
-
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printPriorAuthResult(result)
+		if failIfRequired && result.Data.PARequired {
+			return &conditionError{reason: "prior authorization is required"}
 		}
+		return nil
 	},
 }
 
@@ -56,8 +69,11 @@ var priorAuthResearchCmd = &cobra.Command{
 	Short: "Research prior auth requirements via AI web search",
 	Long:  "Use AI-powered web research to find prior authorization requirements from payer websites",
 	Args:  cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		c := client.New(getAPIKey(), getBaseURL())
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
 
 		payer, _ := cmd.Flags().GetString("payer")
 		state, _ := cmd.Flags().GetString("state")
@@ -65,37 +81,34 @@ var priorAuthResearchCmd = &cobra.Command{
 		clinicalContext, _ := cmd.Flags().GetString("context")
 		syncMode, _ := cmd.Flags().GetBool("sync")
 
-		reqBody := map[string]interface{}{
-			"procedure_codes": args,
-			"sync":            syncMode,
-		}
-
-		if payer != "" {
-			reqBody["payer"] = payer
-		}
-		if state != "" {
-			reqBody["state"] = state
+		wait := defaultTimeout
+		if syncMode {
+			wait = researchSyncTimeout
 		}
-		if len(diagnosisCodes) > 0 {
-			reqBody["diagnosis_codes"] = diagnosisCodes
-		}
-		if clinicalContext != "" {
-			reqBody["clinical_context"] = clinicalContext
-		}
-
-		var result map[string]interface{}
-		if err := c.Post("/prior-auth/research", reqBody, &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
+		ctx, cancel := commandContext(cmd, wait)
+		defer cancel()
+
+		result, err := c.StartResearch(ctx, client.ResearchRequest{
+			ProcedureCodes:  args,
+			Payer:           payer,
+			State:           state,
+			DiagnosisCodes:  diagnosisCodes,
+			ClinicalContext: clinicalContext,
+			Sync:            syncMode,
+		})
+		if err != nil {
+			return err
 		}
 
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printResearchResult(result)
-		}
+		return render(&output.View{
+			Data: result,
+			Table: func(w io.Writer) {
+				printResearchResult(w, result.Data)
+			},
+			Document: func() *output.Document {
+				return researchDocument(result.Data)
+			},
+		})
 	},
 }
 
@@ -104,24 +117,29 @@ var priorAuthResearchStatusCmd = &cobra.Command{
 	Short: "Get prior auth research status",
 	Long:  "Poll the status and results of a prior authorization research task",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		c := client.New(getAPIKey(), getBaseURL())
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
 
-		path := fmt.Sprintf("/prior-auth/research/%s", args[0])
+		ctx, cancel := commandContext(cmd, defaultTimeout)
+		defer cancel()
 
-		var result map[string]interface{}
-		if err := c.Get(path, &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
+		result, err := c.GetResearch(ctx, args[0])
+		if err != nil {
+			return err
 		}
 
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printResearchResult(result)
-		}
+		return render(&output.View{
+			Data: result,
+			Table: func(w io.Writer) {
+				printResearchResult(w, result.Data)
+			},
+			Document: func() *output.Document {
+				return researchDocument(result.Data)
+			},
+		})
 	},
 }
 
@@ -133,6 +151,7 @@ func init() {
 	priorAuthCmd.Flags().StringSliceP("diagnosis", "d", []string{}, "Diagnosis codes (ICD-10)")
 	priorAuthCmd.Flags().StringP("state", "s", "", "Two-letter state code")
 	priorAuthCmd.Flags().StringP("payer", "p", "medicare", "Payer (medicare, aetna, uhc, all)")
+	priorAuthCmd.Flags().Bool("fail-if-required", false, "Exit with status 10 if prior authorization is required")
 
 	priorAuthResearchCmd.Flags().StringP("payer", "p", "", "Payer name (e.g., UnitedHealthcare, Aetna)")
 	priorAuthResearchCmd.Flags().StringP("state", "s", "", "Two-letter state code")
@@ -141,77 +160,135 @@ func init() {
 	priorAuthResearchCmd.Flags().Bool("sync", false, "Wait for completion instead of returning research ID")
 }
 
-func printPriorAuthResult(result map[string]interface{}) {
-	data, ok := result["data"].(map[string]interface{})
-	if !ok {
-		fmt.Println("Invalid response format")
-		return
-	}
-
-	fmt.Printf("Prior Authorization Required: %v\n", data["pa_required"])
-	fmt.Printf("Confidence: %v\n", data["confidence"])
-	fmt.Printf("Reason: %v\n\n", data["reason"])
+func printPriorAuthResult(w io.Writer, data client.PriorAuthResult) {
+	fmt.Fprintf(w, "Prior Authorization Required: %s\n", paStatus(data.PARequired))
+	fmt.Fprintf(w, "Confidence: %s\n", data.Confidence)
+	fmt.Fprintf(w, "Reason: %s\n\n", data.Reason)
 
-	if policies, ok := data["matched_policies"].([]interface{}); ok && len(policies) > 0 {
-		fmt.Println("Matched Policies:")
-		for _, p := range policies {
-			policy := p.(map[string]interface{})
-			fmt.Printf("  - %s: %s\n", policy["policy_id"], policy["title"])
+	if len(data.MatchedPolicies) > 0 {
+		fmt.Fprintln(w, "Matched Policies:")
+		for _, policy := range data.MatchedPolicies {
+			fmt.Fprintf(w, "  - %s: %s\n", policy.PolicyID, policy.Title)
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 
-	if checklist, ok := data["documentation_checklist"].([]interface{}); ok && len(checklist) > 0 {
-		fmt.Println("Documentation Checklist:")
-		for _, item := range checklist {
-			fmt.Printf("  - %v\n", item)
+	if len(data.DocumentationChecklist) > 0 {
+		fmt.Fprintln(w, "Documentation Checklist:")
+		for _, item := range data.DocumentationChecklist {
+			fmt.Fprintf(w, "  - %s\n", item)
 		}
 	}
 }
 
-func printResearchResult(result map[string]interface{}) {
-	data, ok := result["data"].(map[string]interface{})
-	if !ok {
-		fmt.Println("Invalid response format")
-		return
+func priorAuthDocument(codes []string, data client.PriorAuthResult) *output.Document {
+	doc := &output.Document{Title: "Prior Authorization: " + strings.Join(codes, ", ")}
+	doc.Add(output.Fields{
+		{Label: "Prior Authorization Required", Value: output.Plain(yesNo(data.PARequired)), Tone: paTone(data.PARequired)},
+		{Label: "Confidence", Value: output.Plain(data.Confidence)},
+		{Label: "Reason", Value: output.Plain(data.Reason)},
+	})
+
+	if len(data.MatchedPolicies) > 0 {
+		table := output.Table{Headers: []string{"Policy", "Title", "Type", "Jurisdiction"}}
+		for _, policy := range data.MatchedPolicies {
+			table.Rows = append(table.Rows, []output.Text{
+				policyLink(policy.PolicyID),
+				output.Plain(policy.Title),
+				output.Plain(policy.PolicyType),
+				output.Plain(policy.Jurisdiction),
+			})
+		}
+		doc.Add(output.Heading{Level: 2, Text: "Matched Policies"}, table)
+	}
+
+	if len(data.DocumentationChecklist) > 0 {
+		checklist := output.Checklist{}
+		for _, item := range data.DocumentationChecklist {
+			checklist = append(checklist, output.Plain(item))
+		}
+		doc.Add(output.Heading{Level: 2, Text: "Documentation Checklist"}, checklist)
 	}
+	return doc
+}
 
-	fmt.Printf("Research ID: %v\n", data["research_id"])
-	fmt.Printf("Status: %v\n", data["status"])
+func printResearchResult(w io.Writer, data client.ResearchTask) {
+	fmt.Fprintf(w, "Research ID: %s\n", data.ResearchID)
+	fmt.Fprintf(w, "Status: %s\n", status(data.Status))
 
-	if createdAt, ok := data["created_at"].(string); ok {
-		fmt.Printf("Created: %s\n", createdAt)
+	if data.CreatedAt != "" {
+		fmt.Fprintf(w, "Created: %s\n", data.CreatedAt)
 	}
 
-	if pollURL, ok := data["poll_url"].(string); ok && pollURL != "" {
-		fmt.Printf("Poll URL: %s\n", pollURL)
-		fmt.Println("\nUse 'verity prior-auth research-status <research-id>' to check progress")
+	if data.PollURL != "" {
+		fmt.Fprintf(w, "Poll URL: %s\n", data.PollURL)
+		fmt.Fprintln(w, "\nUse 'verity prior-auth research-status <research-id>' to check progress")
 	}
 
-	if resResult, ok := data["result"].(map[string]interface{}); ok {
-		fmt.Println("\nResults:")
-		if determination, ok := resResult["determination"].(map[string]interface{}); ok {
-			fmt.Printf("  PA Required: %v\n", determination["pa_required"])
-			fmt.Printf("  Confidence: %v\n", determination["confidence"])
-			fmt.Printf("  Reasoning: %v\n", determination["reasoning"])
+	if res := data.Result; res != nil {
+		fmt.Fprintln(w, "\nResults:")
+		if det := res.Determination; det != nil {
+			fmt.Fprintf(w, "  PA Required: %s\n", paStatus(det.PARequired))
+			fmt.Fprintf(w, "  Confidence: %s\n", det.Confidence)
+			fmt.Fprintf(w, "  Reasoning: %s\n", det.Reasoning)
 		}
 
-		if docReqs, ok := resResult["documentation_requirements"].([]interface{}); ok && len(docReqs) > 0 {
-			fmt.Println("\n  Documentation Requirements:")
-			for _, req := range docReqs {
-				fmt.Printf("    - %v\n", req)
+		if len(res.DocumentationRequirements) > 0 {
+			fmt.Fprintln(w, "\n  Documentation Requirements:")
+			for _, req := range res.DocumentationRequirements {
+				fmt.Fprintf(w, "    - %s\n", req)
 			}
 		}
 
-		if sources, ok := resResult["sources"].([]interface{}); ok && len(sources) > 0 {
-			fmt.Println("\n  Sources:")
-			for _, src := range sources {
-				fmt.Printf("    - %v\n", src)
+		if len(res.Sources) > 0 {
+			fmt.Fprintln(w, "\n  Sources:")
+			for _, src := range res.Sources {
+				fmt.Fprintf(w, "    - %s\n", src)
 			}
 		}
 	}
 
-	if errMsg, ok := data["error"].(string); ok && errMsg != "" {
-		fmt.Printf("\nError: %s\n", errMsg)
+	if data.Error != "" {
+		fmt.Fprintf(w, "\nError: %s\n", red(data.Error))
+	}
+}
+
+func researchDocument(data client.ResearchTask) *output.Document {
+	doc := &output.Document{Title: "Prior Authorization Research " + data.ResearchID}
+	doc.Add(output.Fields{
+		{Label: "Status", Value: output.Plain(data.Status), Tone: statusTone(data.Status)},
+		{Label: "Created", Value: output.Plain(data.CreatedAt)},
+		{Label: "Error", Value: output.Plain(data.Error), Tone: output.ToneBad},
+	})
+
+	res := data.Result
+	if res == nil {
+		return doc
+	}
+
+	if det := res.Determination; det != nil {
+		doc.Add(
+			output.Heading{Level: 2, Text: "Determination"},
+			output.Fields{
+				{Label: "Prior Authorization Required", Value: output.Plain(yesNo(det.PARequired)), Tone: paTone(det.PARequired)},
+				{Label: "Confidence", Value: output.Plain(det.Confidence)},
+			},
+		)
+		if det.Reasoning != "" {
+			doc.Add(output.Paragraph{Text: output.Plain(det.Reasoning)})
+		}
+	}
+
+	if len(res.DocumentationRequirements) > 0 {
+		checklist := output.Checklist{}
+		for _, req := range res.DocumentationRequirements {
+			checklist = append(checklist, output.Plain(req))
+		}
+		doc.Add(output.Heading{Level: 2, Text: "Documentation Requirements"}, checklist)
+	}
+
+	if len(res.Sources) > 0 {
+		doc.Add(output.Heading{Level: 2, Text: "Sources"}, sourceList(res.Sources))
 	}
+	return doc
 }
@@ -1,20 +1,50 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/tylerbryy/verity-cli/pkg/client"
+	"github.com/tylerbryy/verity-cli/pkg/output"
 )
 
 var (
-	cfgFile string
-	apiKey  string
-	baseURL string
-	output  string
+	cfgFile      string
+	apiKey       string
+	baseURL      string
+	outputFormat string
+	timeout      time.Duration
+	maxRetries   int
+	recordDir    string
+	replayDir    string
+	dryRun       bool
+	printCurl    bool
 )
 
+// Per-command request timeouts, used unless --timeout or the timeout config
+// key overrides them.
+const (
+	defaultTimeout      = 30 * time.Second
+	healthTimeout       = 10 * time.Second
+	researchSyncTimeout = 5 * time.Minute
+)
+
+// ErrCancelled is returned by Execute when the run was interrupted by
+// SIGINT or SIGTERM.
+var ErrCancelled = errors.New("cancelled")
+
+// commandStarted is set once cobra has validated the invocation, so errors
+// returned before it is set are usage errors.
+var commandStarted bool
+
 var rootCmd = &cobra.Command{
 	Use:   "verity",
 	Short: "Verity CLI - Medicare coverage policies and prior authorization",
@@ -22,23 +52,87 @@ var rootCmd = &cobra.Command{
 requirements, and medical code lookups from the command line.
 
 Get your API key from: https://verity.backworkai.com/dashboard`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Restore default signal handling once cancelled so a second Ctrl-C
+	// kills the process outright.
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// Commands that are meant to run until interrupted, such as
+		// mock serve, return nil when they stop cleanly.
+		err = ErrCancelled
+	case errors.Is(err, client.ErrDryRun):
+		// The request was printed instead of sent.
+		err = nil
+	case err != nil && !commandStarted:
+		// Cobra rejected the arguments or flags before the command ran.
+		err = &usageError{err: err}
+	}
+	if err != nil {
+		reportError(cmd, err)
+	}
+	return err
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
 
+	// Assigned here rather than in the literal because setupOutput reads
+	// rootCmd's flags.
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		// Cobra checks required flags and flag groups only after this
+		// hook, so check them here while errors still count as usage
+		// errors.
+		if err := cmd.ValidateRequiredFlags(); err != nil {
+			return &usageError{err: err}
+		}
+		if err := cmd.ValidateFlagGroups(); err != nil {
+			return &usageError{err: err}
+		}
+		commandStarted = true
+		if recordDir != "" && replayDir != "" {
+			return usageErrorf("--record and --replay are mutually exclusive")
+		}
+		if err := setupOutput(cmd, args); err != nil {
+			return err
+		}
+		return setupColor()
+	}
+
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.verity.yaml)")
 	rootCmd.PersistentFlags().StringVar(&apiKey, "api-key", "", "Verity API key (or set VERITY_API_KEY env var)")
 	rootCmd.PersistentFlags().StringVar(&baseURL, "base-url", "https://verity.backworkai.com/api/v1", "API base URL")
-	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "table", "Output format (table, json, yaml)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "Output format ("+strings.Join(output.Names(), ", ")+")")
+	rootCmd.PersistentFlags().IntVar(&maxRetries, "max-retries", client.DefaultRetryPolicy.MaxRetries, "Retries for rate-limited or failed requests (0 disables)")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Request timeout, e.g. 10s or 2m (default depends on the command)")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "Save every HTTP exchange to this directory, with the API key removed")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "Answer requests from exchanges saved with --record, without network access")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the method, URL and JSON body of the request instead of sending it")
+	rootCmd.PersistentFlags().BoolVar(&printCurl, "print-curl", false, "Print an equivalent curl command instead of sending the request")
+	rootCmd.PersistentFlags().Bool("debug", false, "Log each HTTP request and response to stderr, with the API key redacted (or set VERITY_DEBUG=1)")
+	rootCmd.PersistentFlags().Bool("debug-body", false, "With --debug, also log response bodies; implies --debug")
 
 	viper.BindPFlag("api_key", rootCmd.PersistentFlags().Lookup("api-key"))
 	viper.BindPFlag("base_url", rootCmd.PersistentFlags().Lookup("base-url"))
 	viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+	viper.BindPFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+	viper.BindPFlag("max_retries", rootCmd.PersistentFlags().Lookup("max-retries"))
+	viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
+	viper.BindPFlag("debug_body", rootCmd.PersistentFlags().Lookup("debug-body"))
+
+	viper.SetDefault("conditional_requests", true)
 }
 
 func initConfig() {
@@ -62,19 +156,95 @@ func initConfig() {
 	viper.ReadInConfig()
 }
 
-func getAPIKey() string {
+func getAPIKey() (string, error) {
 	key := viper.GetString("api_key")
 	if key == "" {
-		fmt.Fprintln(os.Stderr, "Error: API key is required. Set VERITY_API_KEY or use --api-key flag")
-		os.Exit(1)
+		return "", errMissingAPIKey
+	}
+	return key, nil
+}
+
+func newClient() (*client.Client, error) {
+	key, err := getAPIKey()
+	switch {
+	case errors.Is(err, errMissingAPIKey) && replayDir != "":
+		// Replayed responses need no credentials.
+		key, err = "replay", nil
+	case errors.Is(err, errMissingAPIKey) && (dryRun || printCurl):
+		// A printed request never shows the key.
+		key, err = "", nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := client.New(key, getBaseURL())
+	c.Retry.MaxRetries = viper.GetInt("max_retries")
+	c.Logf = func(format string, args ...interface{}) {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+	}
+	if dryRun || printCurl {
+		// Nothing is sent, so nothing may be answered from a local store
+		// either: the request is printed exactly as the API would get it.
+		c.HTTPClient.Transport = client.NewDryRun(os.Stdout, printCurl)
+		return c, nil
+	}
+	if recordDir != "" || replayDir != "" {
+		// Every request has to reach the cassette, and a replay cannot
+		// depend on what a local store held when it was recorded.
+		c.HTTPClient.Transport = newCassette()
+	} else {
+		if c.Cache, err = clientCache(); err != nil {
+			return nil, err
+		}
+		if c.Validators, err = clientValidators(); err != nil {
+			return nil, err
+		}
+	}
+
+	if bodies := viper.GetBool("debug_body"); bodies || viper.GetBool("debug") {
+		c.HTTPClient.Transport = client.NewTracer(c.HTTPClient.Transport, os.Stderr, bodies)
+		c.Debugf = func(format string, args ...interface{}) {
+			fmt.Fprintf(os.Stderr, "* "+format+"\n\n", args...)
+		}
 	}
-	return key
+	return c, nil
+}
+
+// newCassette returns the transport for --record or --replay.
+func newCassette() *client.Cassette {
+	if replayDir != "" {
+		return client.NewCassette(replayDir, client.Replay)
+	}
+	return client.NewCassette(recordDir, client.Record)
 }
 
 func getBaseURL() string {
 	return viper.GetString("base_url")
 }
 
+// getOutput returns the output format. Unless --output was given
+// explicitly, passing a template implies the template format, and an
+// --output-file extension such as .csv implies that format.
 func getOutput() string {
+	flags := rootCmd.PersistentFlags()
+	if !flags.Changed("output") {
+		if flags.Changed("template") || flags.Changed("template-file") {
+			return "template"
+		}
+		if format := output.FormatForFile(outputFile); format != "" {
+			return format
+		}
+	}
 	return viper.GetString("output")
 }
+
+// commandContext derives the request context for cmd from the root context,
+// bounded by --timeout or, when unset, by the command's own default.
+func commandContext(cmd *cobra.Command, fallback time.Duration) (context.Context, context.CancelFunc) {
+	d := viper.GetDuration("timeout")
+	if d <= 0 {
+		d = fallback
+	}
+	return context.WithTimeout(cmd.Context(), d)
+}
@@ -1,12 +1,13 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"strings"
+	"io"
+	"sort"
 
 	"github.com/spf13/cobra"
 	"github.com/tylerbryy/verity-cli/pkg/client"
+	"github.com/tylerbryy/verity-cli/pkg/output"
 )
 
 var spendingCmd = &cobra.Command{
@@ -14,34 +15,32 @@ var spendingCmd = &cobra.Command{
 	Short: "Get Medicaid spending data by HCPCS code",
 	Long:  "Returns aggregate Medicaid provider spending statistics per HCPCS code",
 	Args:  cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		c := client.New(getAPIKey(), getBaseURL())
-
-		var path string
-		if len(args) == 1 {
-			path = fmt.Sprintf("/spending/by-code?code=%s", args[0])
-		} else {
-			path = fmt.Sprintf("/spending/by-code?codes=%s", strings.Join(args, ","))
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
 		}
 
 		year, _ := cmd.Flags().GetInt("year")
-		if year > 0 {
-			path += fmt.Sprintf("&year=%d", year)
-		}
 
-		var result map[string]interface{}
-		if err := c.Get(path, &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
-		}
+		ctx, cancel := commandContext(cmd, defaultTimeout)
+		defer cancel()
 
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printSpendingResult(result)
+		result, err := c.SpendingByCode(ctx, args, &client.SpendingOptions{Year: year})
+		if err != nil {
+			return err
 		}
+
+		return render(&output.View{
+			Data:    result,
+			Records: spendingRows(result.Data),
+			Sheets: func() []output.Sheet {
+				return spendingSheets(result.Data)
+			},
+			Table: func(w io.Writer) {
+				printSpendingResult(w, result.Data)
+			},
+		})
 	},
 }
 
@@ -51,32 +50,78 @@ func init() {
 	spendingCmd.Flags().IntP("year", "y", 0, "Filter to a specific year")
 }
 
-func printSpendingResult(result map[string]interface{}) {
-	data, ok := result["data"].(map[string]interface{})
-	if !ok {
-		fmt.Println("No spending data found")
+func printSpendingResult(w io.Writer, data map[string]client.SpendingSummary) {
+	if len(data) == 0 {
+		fmt.Fprintln(w, "No spending data found")
 		return
 	}
 
-	for code, info := range data {
-		spending, ok := info.(map[string]interface{})
-		if !ok {
-			continue
-		}
+	for _, code := range sortedCodes(data) {
+		spending := data[code]
 
-		fmt.Printf("Code: %s\n", code)
-		fmt.Printf("  Total Paid: $%v\n", spending["total_paid"])
-		fmt.Printf("  Total Claims: %v\n", spending["total_claims"])
-		fmt.Printf("  Unique Beneficiaries: %v\n", spending["unique_beneficiaries"])
-		fmt.Printf("  Unique Providers: %v\n", spending["unique_providers"])
-
-		if byYear, ok := spending["by_year"].([]interface{}); ok && len(byYear) > 0 {
-			fmt.Println("  By Year:")
-			for _, y := range byYear {
-				yr := y.(map[string]interface{})
-				fmt.Printf("    %v: $%v (%v claims)\n", yr["year"], yr["total_paid"], yr["total_claims"])
+		fmt.Fprintf(w, "Code: %s\n", code)
+		fmt.Fprintf(w, "  Total Paid: $%.2f\n", spending.TotalPaid)
+		fmt.Fprintf(w, "  Total Claims: %d\n", spending.TotalClaims)
+		fmt.Fprintf(w, "  Unique Beneficiaries: %d\n", spending.UniqueBeneficiaries)
+		fmt.Fprintf(w, "  Unique Providers: %d\n", spending.UniqueProviders)
+
+		if len(spending.ByYear) > 0 {
+			fmt.Fprintln(w, "  By Year:")
+			for _, yr := range spending.ByYear {
+				fmt.Fprintf(w, "    %d: $%.2f (%d claims)\n", yr.Year, yr.TotalPaid, yr.TotalClaims)
 			}
 		}
-		fmt.Println("---")
+		fmt.Fprintln(w, "---")
+	}
+}
+
+// spendingRow is one code's summary as a flat record for row-based output.
+type spendingRow struct {
+	Code string `json:"code"`
+	client.SpendingSummary
+}
+
+func spendingRows(data map[string]client.SpendingSummary) []spendingRow {
+	rows := make([]spendingRow, 0, len(data))
+	for _, code := range sortedCodes(data) {
+		rows = append(rows, spendingRow{Code: code, SpendingSummary: data[code]})
+	}
+	return rows
+}
+
+// spendingSheets puts the per-code totals on a summary sheet followed by a
+// sheet of yearly figures for each code.
+func spendingSheets(data map[string]client.SpendingSummary) []output.Sheet {
+	sheets := []output.Sheet{{
+		Name:    "Summary",
+		Records: spendingRows(data),
+		Columns: []output.Column{
+			{Field: "code", Header: "Code"},
+			{Field: "total_paid", Header: "Total Paid"},
+			{Field: "total_claims", Header: "Total Claims"},
+			{Field: "unique_beneficiaries", Header: "Unique Beneficiaries"},
+			{Field: "unique_providers", Header: "Unique Providers"},
+		},
+	}}
+	for _, code := range sortedCodes(data) {
+		sheets = append(sheets, output.Sheet{
+			Name:    code,
+			Records: data[code].ByYear,
+			Columns: []output.Column{
+				{Field: "year", Header: "Year"},
+				{Field: "total_paid", Header: "Total Paid"},
+				{Field: "total_claims", Header: "Total Claims"},
+			},
+		})
+	}
+	return sheets
+}
+
+func sortedCodes(data map[string]client.SpendingSummary) []string {
+	codes := make([]string, 0, len(data))
+	for code := range data {
+		codes = append(codes, code)
 	}
+	sort.Strings(codes)
+	return codes
 }
@@ -0,0 +1,26 @@
+//go:build !unix
+
+package cmd
+
+import (
+	"os"
+	"strconv"
+)
+
+// terminalSize returns f's size in columns and rows, or ok == false when f
+// is not a terminal. Without a portable size query it trusts $COLUMNS and
+// $LINES, falling back to 80x24.
+func terminalSize(f *os.File) (width, height int, ok bool) {
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return 0, 0, false
+	}
+	width, height = 80, 24
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		width = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+		height = n
+	}
+	return width, height, true
+}
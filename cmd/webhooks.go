@@ -1,12 +1,13 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/tylerbryy/verity-cli/pkg/client"
+	"github.com/tylerbryy/verity-cli/pkg/output"
 )
 
 var webhooksCmd = &cobra.Command{
@@ -18,52 +19,58 @@ var webhooksCmd = &cobra.Command{
 var webhooksListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all webhooks",
-	Run: func(cmd *cobra.Command, args []string) {
-		c := client.New(getAPIKey(), getBaseURL())
-
-		var result map[string]interface{}
-		if err := c.Get("/webhooks", &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
 		}
 
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printWebhooksList(result)
+		ctx, cancel := commandContext(cmd, defaultTimeout)
+		defer cancel()
+
+		result, err := c.ListWebhooks(ctx)
+		if err != nil {
+			return err
 		}
+
+		return render(&output.View{
+			Data:    result,
+			Records: result.Data,
+			Columns: webhookColumns,
+			Empty:   "No webhooks found",
+		})
 	},
 }
 
 var webhooksCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new webhook",
-	Run: func(cmd *cobra.Command, args []string) {
-		c := client.New(getAPIKey(), getBaseURL())
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
 
 		url, _ := cmd.Flags().GetString("url")
 		events, _ := cmd.Flags().GetString("events")
 
-		reqBody := map[string]interface{}{
-			"url":    url,
-			"events": strings.Split(events, ","),
-		}
+		ctx, cancel := commandContext(cmd, defaultTimeout)
+		defer cancel()
 
-		var result map[string]interface{}
-		if err := c.Post("/webhooks", reqBody, &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
+		result, err := c.CreateWebhook(ctx, client.WebhookRequest{
+			URL:    url,
+			Events: strings.Split(events, ","),
+		})
+		if err != nil {
+			return err
 		}
 
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printWebhookDetail(result)
-		}
+		return render(&output.View{
+			Data: result,
+			Table: func(w io.Writer) {
+				printWebhookDetail(w, result.Data)
+			},
+		})
 	},
 }
 
@@ -71,37 +78,36 @@ var webhooksUpdateCmd = &cobra.Command{
 	Use:   "update [id]",
 	Short: "Update a webhook",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		webhookID := args[0]
-		c := client.New(getAPIKey(), getBaseURL())
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
 
-		reqBody := map[string]interface{}{}
+		req := client.WebhookRequest{}
 
 		url, _ := cmd.Flags().GetString("url")
-		if url != "" {
-			reqBody["url"] = url
-		}
+		req.URL = url
 
 		events, _ := cmd.Flags().GetString("events")
 		if events != "" {
-			reqBody["events"] = strings.Split(events, ",")
+			req.Events = strings.Split(events, ",")
 		}
 
-		path := fmt.Sprintf("/webhooks/%s", webhookID)
+		ctx, cancel := commandContext(cmd, defaultTimeout)
+		defer cancel()
 
-		var result map[string]interface{}
-		if err := c.Request("PATCH", path, reqBody, &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
+		result, err := c.UpdateWebhook(ctx, args[0], req)
+		if err != nil {
+			return err
 		}
 
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printWebhookDetail(result)
-		}
+		return render(&output.View{
+			Data: result,
+			Table: func(w io.Writer) {
+				printWebhookDetail(w, result.Data)
+			},
+		})
 	},
 }
 
@@ -109,25 +115,27 @@ var webhooksDeleteCmd = &cobra.Command{
 	Use:   "delete [id]",
 	Short: "Delete a webhook",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		webhookID := args[0]
-		c := client.New(getAPIKey(), getBaseURL())
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
 
-		path := fmt.Sprintf("/webhooks/%s", webhookID)
+		ctx, cancel := commandContext(cmd, defaultTimeout)
+		defer cancel()
 
-		var result map[string]interface{}
-		if err := c.Request("DELETE", path, nil, &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
+		result, err := c.DeleteWebhook(ctx, webhookID)
+		if err != nil {
+			return err
 		}
 
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			fmt.Printf("Webhook %s deleted successfully\n", webhookID)
-		}
+		return render(&output.View{
+			Data: result,
+			Table: func(w io.Writer) {
+				fmt.Fprintf(w, "Webhook %s deleted successfully\n", webhookID)
+			},
+		})
 	},
 }
 
@@ -135,25 +143,26 @@ var webhooksTestCmd = &cobra.Command{
 	Use:   "test [id]",
 	Short: "Send a test event to a webhook",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		webhookID := args[0]
-		c := client.New(getAPIKey(), getBaseURL())
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
 
-		path := fmt.Sprintf("/webhooks/%s/test", webhookID)
+		ctx, cancel := commandContext(cmd, defaultTimeout)
+		defer cancel()
 
-		var result map[string]interface{}
-		if err := c.Post(path, nil, &result); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
+		result, err := c.TestWebhook(ctx, args[0])
+		if err != nil {
+			return err
 		}
 
-		output := getOutput()
-		if output == "json" {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(jsonData))
-		} else {
-			printWebhookTestResult(result)
-		}
+		return render(&output.View{
+			Data: result,
+			Table: func(w io.Writer) {
+				printWebhookTestResult(w, result.Data)
+			},
+		})
 	},
 }
 
@@ -174,77 +183,38 @@ func init() {
 	webhooksUpdateCmd.Flags().String("events", "", "New comma-separated event types")
 }
 
-func printWebhooksList(result map[string]interface{}) {
-	data, ok := result["data"].([]interface{})
-	if !ok || len(data) == 0 {
-		fmt.Println("No webhooks found")
-		return
-	}
-
-	fmt.Printf("Found %d webhooks:\n\n", len(data))
-	for _, w := range data {
-		webhook := w.(map[string]interface{})
-		fmt.Printf("ID: %v\n", webhook["id"])
-		fmt.Printf("URL: %v\n", webhook["url"])
-		if events, ok := webhook["events"].([]interface{}); ok {
-			eventStrs := make([]string, len(events))
-			for i, e := range events {
-				eventStrs[i] = fmt.Sprintf("%v", e)
-			}
-			fmt.Printf("Events: %s\n", strings.Join(eventStrs, ", "))
-		}
-		if status, ok := webhook["status"].(string); ok {
-			fmt.Printf("Status: %s\n", status)
-		}
-		if createdAt, ok := webhook["created_at"].(string); ok {
-			fmt.Printf("Created: %s\n", createdAt)
-		}
-		fmt.Println("---")
-	}
+var webhookColumns = []output.Column{
+	{Field: "id"},
+	{Field: "url"},
+	{Field: "events"},
+	{Field: "status"},
+	{Field: "created_at", Header: "CREATED", Wide: true},
 }
 
-func printWebhookDetail(result map[string]interface{}) {
-	data, ok := result["data"].(map[string]interface{})
-	if !ok {
-		fmt.Println("Invalid response format")
-		return
+func printWebhookDetail(w io.Writer, data client.Webhook) {
+	fmt.Fprintf(w, "ID: %s\n", data.ID)
+	fmt.Fprintf(w, "URL: %s\n", data.URL)
+	fmt.Fprintf(w, "Events: %s\n", strings.Join(data.Events, ", "))
+	if data.Status != "" {
+		fmt.Fprintf(w, "Status: %s\n", data.Status)
 	}
-
-	fmt.Printf("ID: %v\n", data["id"])
-	fmt.Printf("URL: %v\n", data["url"])
-	if events, ok := data["events"].([]interface{}); ok {
-		eventStrs := make([]string, len(events))
-		for i, e := range events {
-			eventStrs[i] = fmt.Sprintf("%v", e)
-		}
-		fmt.Printf("Events: %s\n", strings.Join(eventStrs, ", "))
-	}
-	if status, ok := data["status"].(string); ok {
-		fmt.Printf("Status: %s\n", status)
+	if data.Secret != "" {
+		fmt.Fprintf(w, "Secret: %s\n", data.Secret)
 	}
-	if secret, ok := data["secret"].(string); ok && secret != "" {
-		fmt.Printf("Secret: %s\n", secret)
-	}
-	if createdAt, ok := data["created_at"].(string); ok {
-		fmt.Printf("Created: %s\n", createdAt)
+	if data.CreatedAt != "" {
+		fmt.Fprintf(w, "Created: %s\n", data.CreatedAt)
 	}
 }
 
-func printWebhookTestResult(result map[string]interface{}) {
-	data, ok := result["data"].(map[string]interface{})
-	if !ok {
-		fmt.Println("Invalid response format")
-		return
-	}
-
-	fmt.Printf("Test Result: %v\n", data["status"])
-	if statusCode, ok := data["status_code"]; ok {
-		fmt.Printf("Response Code: %v\n", statusCode)
+func printWebhookTestResult(w io.Writer, data client.WebhookTestResult) {
+	fmt.Fprintf(w, "Test Result: %s\n", status(data.Status))
+	if data.StatusCode != 0 {
+		fmt.Fprintf(w, "Response Code: %d\n", data.StatusCode)
 	}
-	if duration, ok := data["duration_ms"]; ok {
-		fmt.Printf("Duration: %vms\n", duration)
+	if data.DurationMs != 0 {
+		fmt.Fprintf(w, "Duration: %vms\n", data.DurationMs)
 	}
-	if errMsg, ok := data["error"].(string); ok && errMsg != "" {
-		fmt.Printf("Error: %s\n", errMsg)
+	if data.Error != "" {
+		fmt.Fprintf(w, "Error: %s\n", red(data.Error))
 	}
 }
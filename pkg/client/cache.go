@@ -0,0 +1,139 @@
+package client
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Cache is an on-disk store of API responses, so repeated calls for data
+// that rarely changes are answered without a request. Entries are keyed by
+// a hash of the API key, method, URL and a hash of the request body, and
+// stay fresh for the TTL of their endpoint.
+type Cache struct {
+	Dir string
+	// TTLs maps endpoints to how long their responses stay fresh. A key
+	// is a path prefix for GET requests, or a method and path such as
+	// "POST /codes/batch" for anything else. The longest matching prefix
+	// wins. Endpoints without an entry, or with a zero TTL, are never
+	// cached.
+	TTLs map[string]time.Duration
+	// Refresh skips cached entries, so every call goes to the API and its
+	// response replaces the stored one.
+	Refresh bool
+}
+
+// DefaultCacheTTLs are the TTLs of a new Cache. Health checks, webhooks,
+// prior-auth, policy comparisons and coverage evaluations are never cached.
+var DefaultCacheTTLs = map[string]time.Duration{
+	"/codes/lookup":      24 * time.Hour,
+	"POST /codes/batch":  24 * time.Hour,
+	"/jurisdictions":     24 * time.Hour,
+	"/spending/by-code":  24 * time.Hour,
+	"/policies":          time.Hour,
+	"/policies/changes":  0,
+	"/coverage/criteria": time.Hour,
+}
+
+// CacheStats summarizes the contents of a cache directory.
+type CacheStats struct {
+	Dir     string     `json:"dir"`
+	Entries int        `json:"entries"`
+	Expired int        `json:"expired"`
+	Bytes   int64      `json:"bytes"`
+	Oldest  *time.Time `json:"oldest,omitempty"`
+	Newest  *time.Time `json:"newest,omitempty"`
+}
+
+// NewCache returns a cache in dir with DefaultCacheTTLs.
+func NewCache(dir string) *Cache {
+	ttls := make(map[string]time.Duration, len(DefaultCacheTTLs))
+	for path, ttl := range DefaultCacheTTLs {
+		ttls[path] = ttl
+	}
+	return &Cache{Dir: dir, TTLs: ttls}
+}
+
+// DefaultCacheDir returns the verity directory under the user's cache
+// directory, such as ~/.cache/verity on Linux.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "verity"), nil
+}
+
+// TTL returns how long responses to method on path stay fresh, or 0 when
+// they are not cached.
+func (c *Cache) TTL(method, path string) time.Duration {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	var ttl time.Duration
+	best := -1
+	for key, d := range c.TTLs {
+		m, prefix, ok := strings.Cut(key, " ")
+		if !ok {
+			m, prefix = http.MethodGet, key
+		}
+		if m != method {
+			continue
+		}
+		if len(prefix) > best && (path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")) {
+			ttl, best = d, len(prefix)
+		}
+	}
+	return ttl
+}
+
+// get returns the stored response for a request made with credential, if
+// it is still fresh.
+func (c *Cache) get(credential, method, url string, payload []byte) ([]byte, bool) {
+	if c.Refresh {
+		return nil, false
+	}
+	entry, err := c.store().read(c.store().file(credential, method, url, payload))
+	if err != nil || time.Now().After(entry.Expires) {
+		return nil, false
+	}
+	return entry.Body, true
+}
+
+// put stores a response for ttl.
+func (c *Cache) put(credential, method, url string, payload, body []byte, ttl time.Duration) error {
+	now := time.Now()
+	return c.store().write(c.store().file(credential, method, url, payload), &cacheEntry{
+		Method:   method,
+		URL:      url,
+		StoredAt: now,
+		Expires:  now.Add(ttl),
+		Body:     body,
+	})
+}
+
+// Stats reports how many entries the cache holds and how much space they
+// use.
+func (c *Cache) Stats() (CacheStats, error) {
+	return c.store().stats()
+}
+
+// Clear removes every entry and returns how many there were.
+func (c *Cache) Clear() (int, error) {
+	return c.store().remove(func(*cacheEntry) bool { return true })
+}
+
+// Prune removes expired and unreadable entries and returns how many it
+// removed.
+func (c *Cache) Prune() (int, error) {
+	now := time.Now()
+	return c.store().remove(func(entry *cacheEntry) bool {
+		return entry == nil || now.After(entry.Expires)
+	})
+}
+
+func (c *Cache) store() diskStore {
+	return diskStore{dir: c.Dir}
+}
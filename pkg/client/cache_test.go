@@ -0,0 +1,80 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCacheTTL(t *testing.T) {
+	c := NewCache(t.TempDir())
+	tests := []struct {
+		method, path string
+		want         time.Duration
+	}{
+		{"GET", "/codes/lookup?code=76942", 24 * time.Hour},
+		{"GET", "/policies", time.Hour},
+		{"GET", "/policies?q=sleep&page=2", time.Hour},
+		{"GET", "/policies/L33831", time.Hour},
+		{"GET", "/policies/changes", 0},
+		{"GET", "/policies/changes?since=2024-01-01", 0},
+		{"GET", "/policiesx", 0},
+		{"GET", "/health", 0},
+		{"GET", "/webhooks", 0},
+		{"POST", "/codes/batch", 24 * time.Hour},
+		{"GET", "/codes/batch", 0},
+		{"POST", "/policies", 0},
+		{"POST", "/prior-auth/check", 0},
+		{"POST", "/coverage/evaluate", 0},
+		{"DELETE", "/policies/L33831", 0},
+	}
+	for _, tt := range tests {
+		if got := c.TTL(tt.method, tt.path); got != tt.want {
+			t.Errorf("TTL(%s %s) = %s, want %s", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCacheScopedByAPIKey(t *testing.T) {
+	c := NewCache(t.TempDir())
+	url := "https://verity.example/api/v1/policies"
+	if err := c.put("key-a", "GET", url, nil, []byte(`{"success": true}`), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.get("key-a", "GET", url, nil); !ok {
+		t.Error("get with the same key missed")
+	}
+	if _, ok := c.get("key-b", "GET", url, nil); ok {
+		t.Error("get with another API key hit the first key's entry")
+	}
+	if _, ok := c.get("key-a", "POST", url, nil); ok {
+		t.Error("get with another method hit")
+	}
+	if _, ok := c.get("key-a", "GET", url, []byte(`{}`)); ok {
+		t.Error("get with another body hit")
+	}
+
+	c.Refresh = true
+	if _, ok := c.get("key-a", "GET", url, nil); ok {
+		t.Error("get with Refresh set hit")
+	}
+}
+
+func TestCachePrune(t *testing.T) {
+	c := NewCache(t.TempDir())
+	for i, ttl := range []time.Duration{-time.Minute, time.Hour} {
+		url := fmt.Sprintf("https://verity.example/api/v1/policies?page=%d", i+1)
+		if err := c.put("test", "GET", url, nil, []byte(`{}`), ttl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := c.Prune()
+	if err != nil || removed != 1 {
+		t.Fatalf("Prune = %d, %v; want 1 expired entry removed", removed, err)
+	}
+	if stats, _ := c.Stats(); stats.Entries != 1 {
+		t.Errorf("Prune left %d entries, want 1", stats.Entries)
+	}
+}
@@ -0,0 +1,231 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CassetteMode selects whether a Cassette records or replays.
+type CassetteMode int
+
+const (
+	// Record sends requests on and saves every exchange.
+	Record CassetteMode = iota
+	// Replay answers requests from saved exchanges without any network
+	// access.
+	Replay
+)
+
+// ErrNotRecorded is returned in Replay mode for a request the cassette has
+// no response for. It is never retried.
+var ErrNotRecorded = errors.New("no recorded response")
+
+// Cassette is an http.RoundTripper that records request/response pairs to
+// a directory, one JSON file each, or serves them back from it. Exchanges
+// are matched on method, path, query and body but not host, so a cassette
+// recorded against one server replays against another with the same base
+// path. The Authorization header is never written to disk.
+//
+// Identical requests are numbered in order, so a poll that sees "pending"
+// and then "completed" replays the same way. Once the recorded responses
+// for a request run out, the last one is repeated.
+type Cassette struct {
+	Dir  string
+	Mode CassetteMode
+	// Transport sends requests while recording. Nil means
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// NewCassette returns a cassette that records to or replays from dir.
+func NewCassette(dir string, mode CassetteMode) *Cassette {
+	return &Cassette{Dir: dir, Mode: mode}
+}
+
+type exchange struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string       `json:"method"`
+	URL    string       `json:"url"`
+	Header http.Header  `json:"header,omitempty"`
+	Body   recordedBody `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	StatusCode int          `json:"status"`
+	Header     http.Header  `json:"header,omitempty"`
+	Body       recordedBody `json:"body,omitempty"`
+}
+
+// recordedBody is a body kept as JSON when it is JSON, so cassettes stay
+// readable and editable, and as a string otherwise.
+type recordedBody []byte
+
+func (b recordedBody) MarshalJSON() ([]byte, error) {
+	if len(b) == 0 {
+		return []byte(`""`), nil
+	}
+	if json.Valid(b) {
+		return b, nil
+	}
+	return json.Marshal(string(b))
+}
+
+func (b *recordedBody) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = []byte(s)
+		return nil
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+// RoundTrip records or replays req.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	key := cassetteKey(req, body)
+	n := c.next(key)
+
+	if c.Mode == Replay {
+		return c.replay(req, key, n)
+	}
+	return c.record(req, body, key, n)
+}
+
+func (c *Cassette) record(req *http.Request, body []byte, key string, n int) (*http.Response, error) {
+	transport := c.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := req.Header.Clone()
+	header.Del("Authorization")
+	ex := exchange{
+		Request: recordedRequest{
+			Method: req.Method,
+			URL:    req.URL.RequestURI(),
+			Header: header,
+			Body:   body,
+		},
+		Response: recordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       respBody,
+		},
+	}
+	data, err := json.MarshalIndent(ex, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(c.Dir, key+"_"+strconv.Itoa(n)+".json"), append(data, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("recording %s %s: %w", req.Method, req.URL.RequestURI(), err)
+	}
+	return resp, nil
+}
+
+func (c *Cassette) replay(req *http.Request, key string, n int) (*http.Response, error) {
+	// Past the last recording, keep serving the last one.
+	var data []byte
+	var err error
+	for ; n >= 1; n-- {
+		data, err = os.ReadFile(filepath.Join(c.Dir, key+"_"+strconv.Itoa(n)+".json"))
+		if err == nil || !os.IsNotExist(err) {
+			break
+		}
+	}
+	if n < 1 {
+		return nil, fmt.Errorf("%w for %s %s in %s", ErrNotRecorded, req.Method, req.URL.RequestURI(), c.Dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ex exchange
+	if err := json.Unmarshal(data, &ex); err != nil {
+		return nil, fmt.Errorf("reading recorded response for %s %s: %w", req.Method, req.URL.RequestURI(), err)
+	}
+	header := ex.Response.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", ex.Response.StatusCode, http.StatusText(ex.Response.StatusCode)),
+		StatusCode:    ex.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(ex.Response.Body)),
+		ContentLength: int64(len(ex.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// next returns how many times key has been seen, including this time.
+func (c *Cassette) next(key string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.calls == nil {
+		c.calls = map[string]int{}
+	}
+	c.calls[key]++
+	return c.calls[key]
+}
+
+// cassetteKey names the files for a request: its method and path, for
+// people browsing the directory, and a hash of everything it is matched on.
+func cassetteKey(req *http.Request, body []byte) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + req.URL.RequestURI() + "\n" + string(body)))
+
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			return r
+		}
+		return '_'
+	}, strings.Trim(req.URL.Path, "/"))
+	if len(slug) > 60 {
+		slug = slug[:60]
+	}
+	return req.Method + "_" + slug + "_" + hex.EncodeToString(sum[:6])
+}
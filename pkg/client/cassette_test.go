@@ -0,0 +1,85 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tylerbryy/verity-cli/pkg/client"
+	"github.com/tylerbryy/verity-cli/pkg/mock"
+)
+
+func TestCassette(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	check := func(c *client.Client, codes ...string) (*client.Response[client.PriorAuthResult], error) {
+		return c.CheckPriorAuth(ctx, client.PriorAuthRequest{ProcedureCodes: codes})
+	}
+
+	live, log := mockAPI(t, mock.Options{})
+	live.HTTPClient.Transport = client.NewCassette(dir, client.Record)
+	recorded, err := live.GetPolicy(ctx, "L33831", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := check(live, "E0601"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := live.GetPolicy(ctx, "NOPE", nil); err == nil {
+		t.Fatal("GetPolicy(NOPE) succeeded against the mock")
+	}
+	if log.count() != 3 {
+		t.Fatalf("recording sent %d requests, want 3", log.count())
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "Bearer") {
+			t.Errorf("%s holds the Authorization header", filepath.Base(f))
+		}
+	}
+
+	// Replay from another host, with no API key and nothing listening.
+	replay := client.New("", "http://127.0.0.1:1"+mock.BasePath)
+	replay.HTTPClient.Transport = client.NewCassette(dir, client.Replay)
+
+	got, err := replay.GetPolicy(ctx, "L33831", nil)
+	if err != nil {
+		t.Fatalf("replaying GetPolicy: %v", err)
+	}
+	if got.Data.Title != recorded.Data.Title {
+		t.Errorf("replayed title = %q, want %q", got.Data.Title, recorded.Data.Title)
+	}
+	// Past the recordings for a request, the last one is repeated.
+	if _, err := replay.GetPolicy(ctx, "L33831", nil); err != nil {
+		t.Errorf("replaying GetPolicy a second time: %v", err)
+	}
+	if _, err := check(replay, "E0601"); err != nil {
+		t.Errorf("replaying CheckPriorAuth: %v", err)
+	}
+	var apiErr *client.APIError
+	if _, err := replay.GetPolicy(ctx, "NOPE", nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("replaying GetPolicy(NOPE) = %v, want the recorded 404", err)
+	}
+
+	for name, call := range map[string]func() error{
+		"another body": func() error { _, err := check(replay, "76942"); return err },
+		"another query": func() error {
+			_, err := replay.GetPolicy(ctx, "L33831", &client.GetPolicyOptions{Include: []string{"criteria"}})
+			return err
+		},
+		"another method": func() error { return replay.Request(ctx, http.MethodDelete, "/policies/L33831", nil, nil) },
+	} {
+		if err := call(); !errors.Is(err, client.ErrNotRecorded) {
+			t.Errorf("%s: error = %v, want ErrNotRecorded", name, err)
+		}
+	}
+}
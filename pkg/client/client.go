@@ -1,7 +1,14 @@
+// Package client is a typed Go client for the Verity API.
+//
+// Endpoint methods share one shape: required identifiers, such as a code
+// or policy ID, are plain arguments; optional query parameters come in an
+// XxxOptions struct passed by pointer, where nil means the defaults; and
+// the body of a POST or PATCH is an XxxRequest struct passed by value.
 package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,87 +16,230 @@ import (
 	"time"
 )
 
+// DefaultTimeout bounds a request whose context carries no deadline of its
+// own.
+const DefaultTimeout = 30 * time.Second
+
 type Client struct {
 	APIKey     string
 	BaseURL    string
 	HTTPClient *http.Client
+	// Timeout is applied to calls whose context has no deadline. Zero
+	// means no limit beyond the context.
+	Timeout time.Duration
+	Retry   RetryPolicy
+	// Logf, when set, receives a line for every retried attempt.
+	Logf func(format string, args ...interface{})
+	// Debugf, when set, receives a line whenever a response comes from a
+	// local store rather than the server.
+	Debugf func(format string, args ...interface{})
+	// Cache, when set, answers repeatable requests from disk while their
+	// endpoint's TTL lasts.
+	Cache *Cache
+	// Validators, when set, makes GET requests conditional on the ETag or
+	// Last-Modified of an earlier response for the same URL.
+	Validators *Validators
 }
 
 type ErrorResponse struct {
 	Success bool `json:"success"`
 	Error   struct {
-		Code    string                 `json:"code"`
-		Message string                 `json:"message"`
-		Hint    string                 `json:"hint,omitempty"`
-		Details map[string]interface{} `json:"details,omitempty"`
+		Code      string                 `json:"code"`
+		Message   string                 `json:"message"`
+		Hint      string                 `json:"hint,omitempty"`
+		Details   map[string]interface{} `json:"details,omitempty"`
+		RequestID string                 `json:"request_id,omitempty"`
 	} `json:"error"`
 }
 
 func New(apiKey, baseURL string) *Client {
 	return &Client{
-		APIKey:  apiKey,
-		BaseURL: baseURL,
-		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		APIKey:     apiKey,
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{},
+		Timeout:    DefaultTimeout,
+		Retry:      DefaultRetryPolicy,
 	}
 }
 
-func (c *Client) Request(method, path string, body interface{}, result interface{}) error {
-	url := c.BaseURL + path
+func (c *Client) Request(ctx context.Context, method, path string, body interface{}, result interface{}, opts ...RequestOption) error {
+	var o requestOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	idempotent := o.idempotent || isIdempotentMethod(method)
 
-	var bodyReader io.Reader
+	if _, ok := ctx.Deadline(); !ok && c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	var payload []byte
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		bodyReader = bytes.NewReader(jsonBody)
+		payload = jsonBody
+	}
+
+	var ttl time.Duration
+	if c.Cache != nil {
+		ttl = c.Cache.TTL(method, path)
+	}
+	if ttl > 0 {
+		if body, ok := c.Cache.get(c.APIKey, method, c.BaseURL+path, payload); ok {
+			if err := c.decodeBody(body, result); err == nil {
+				c.debugf("%s %s answered from the response cache", method, c.BaseURL+path)
+				return nil
+			}
+		}
+	}
+
+	var stored *cacheEntry
+	var header http.Header
+	if c.Validators != nil && method == http.MethodGet {
+		if stored = c.Validators.lookup(c.APIKey, c.BaseURL+path); stored != nil {
+			header = stored.conditionalHeader()
+		}
 	}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	for attempt := 0; ; attempt++ {
+		resp, respBody, err := c.send(ctx, method, path, payload, header)
+		if attempt < c.Retry.MaxRetries && shouldRetry(ctx, resp, err, idempotent) {
+			wait := c.Retry.delay(attempt, resp)
+			// A retry that could not start before the deadline would only
+			// turn this attempt's error into a timeout, so report it now.
+			if deadline, ok := ctx.Deadline(); !ok || time.Now().Add(wait).Before(deadline) {
+				var reason string
+				if err != nil {
+					reason = err.Error()
+				} else {
+					reason = fmt.Sprintf("HTTP %d", resp.StatusCode)
+				}
+				c.logf("Retrying %s %s in %s (attempt %d of %d): %s",
+					method, path, wait.Round(time.Millisecond), attempt+2, c.Retry.MaxRetries+1, reason)
+				if err := sleep(ctx, wait); err != nil {
+					return fmt.Errorf("request failed: %w", err)
+				}
+				continue
+			}
+		}
+		if err != nil {
+			return err
+		}
+		return c.finish(method, path, payload, resp, respBody, stored, ttl, result)
+	}
+}
+
+// finish decodes the final response into result, answering 304 Not
+// Modified from the stored response, and records it for later requests.
+func (c *Client) finish(method, path string, payload []byte, resp *http.Response, respBody []byte, stored *cacheEntry, ttl time.Duration, result interface{}) error {
+	url := c.BaseURL + path
+	if stored != nil && resp.StatusCode == http.StatusNotModified {
+		if err := c.decodeBody(stored.Body, result); err != nil {
+			return err
+		}
+		c.Validators.touch(c.APIKey, url)
+		c.debugf("%s %s not modified; using the stored response", method, url)
+		respBody = stored.Body
+	} else {
+		if err := c.decodeResponse(resp, respBody, result); err != nil {
+			return err
+		}
+		if c.Validators != nil && method == http.MethodGet {
+			if err := c.Validators.save(c.APIKey, url, resp, respBody); err != nil {
+				c.logf("Could not store %s %s: %v", method, path, err)
+			}
+		}
+	}
+
+	if ttl > 0 {
+		if err := c.Cache.put(c.APIKey, method, url, payload, respBody, ttl); err != nil {
+			c.logf("Could not cache %s %s: %v", method, path, err)
+		}
+	}
+	return nil
+}
+
+// send performs a single attempt and reads the whole response body. header
+// is added to the request's own headers.
+func (c *Client) send(ctx context.Context, method, path string, payload []byte, header http.Header) (*http.Response, []byte, error) {
+	url := c.BaseURL + path
+
+	var bodyReader io.Reader
+	if payload != nil {
+		bodyReader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	for key, values := range header {
+		req.Header[key] = values
+	}
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("User-Agent", "verity-cli/1.0.0")
-	if body != nil {
+	if payload != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return nil, nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
+	return resp, respBody, nil
+}
+
+func (c *Client) decodeResponse(resp *http.Response, respBody []byte, result interface{}) error {
 	if resp.StatusCode != http.StatusOK {
-		var errResp ErrorResponse
-		if err := json.Unmarshal(respBody, &errResp); err == nil && !errResp.Success {
-			return fmt.Errorf("%s: %s", errResp.Error.Code, errResp.Error.Message)
-		}
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
+		return newAPIError(resp, respBody)
 	}
+	return c.decodeBody(respBody, result)
+}
 
-	if result != nil {
-		if err := json.Unmarshal(respBody, result); err != nil {
-			return fmt.Errorf("failed to parse response: %w", err)
-		}
+// decodeBody decodes a response body into result. A Response tolerates
+// fields whose type has changed upstream; they are reported here so the
+// zero values in table output are not mistaken for real ones.
+func (c *Client) decodeBody(body []byte, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if r, ok := result.(interface{ Mismatch() error }); ok && r.Mismatch() != nil {
+		c.logf("Warning: %v in the response; use -o json to see it as sent", r.Mismatch())
 	}
-
 	return nil
 }
 
-func (c *Client) Get(path string, result interface{}) error {
-	return c.Request("GET", path, nil, result)
+func (c *Client) logf(format string, args ...interface{}) {
+	if c.Logf != nil {
+		c.Logf(format, args...)
+	}
+}
+
+func (c *Client) debugf(format string, args ...interface{}) {
+	if c.Debugf != nil {
+		c.Debugf(format, args...)
+	}
+}
+
+func (c *Client) Get(ctx context.Context, path string, result interface{}, opts ...RequestOption) error {
+	return c.Request(ctx, "GET", path, nil, result, opts...)
 }
 
-func (c *Client) Post(path string, body interface{}, result interface{}) error {
-	return c.Request("POST", path, body, result)
+func (c *Client) Post(ctx context.Context, path string, body interface{}, result interface{}, opts ...RequestOption) error {
+	return c.Request(ctx, "POST", path, body, result, opts...)
 }
@@ -0,0 +1,268 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tylerbryy/verity-cli/pkg/client"
+	"github.com/tylerbryy/verity-cli/pkg/mock"
+)
+
+// The tests in this package run the client against the mock API. They are
+// outside package client because the mock imports it.
+
+// requestLog records the requests that reached the mock API and the status
+// each was answered with.
+type requestLog struct {
+	mu       sync.Mutex
+	requests []string
+	statuses []int
+}
+
+func (l *requestLog) add(r *http.Request, status int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.requests = append(l.requests, r.Method+" "+r.URL.RequestURI())
+	l.statuses = append(l.statuses, status)
+}
+
+// count returns how many requests have reached the server.
+func (l *requestLog) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.requests)
+}
+
+// last returns the status of the latest request.
+func (l *requestLog) last() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.statuses) == 0 {
+		return 0
+	}
+	return l.statuses[len(l.statuses)-1]
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// mockAPI starts the mock API with the built-in fixtures and returns a
+// client for it, with fast retries, and the log of requests it received.
+func mockAPI(t *testing.T, opts mock.Options) (*client.Client, *requestLog) {
+	t.Helper()
+	fixtures, err := mock.LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := mock.NewServer(fixtures, opts)
+	log := &requestLog{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		api.ServeHTTP(sw, r)
+		log.add(r, sw.status)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := client.New("test", srv.URL+mock.BasePath)
+	c.Retry = client.RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	return c, log
+}
+
+func TestRetry(t *testing.T) {
+	// The mock sends Retry-After: 1 with a 429, which MaxDelay cuts to
+	// 10ms; without the cap these tests would take seconds.
+	failing := map[string]int{"/health": 503, "/prior-auth/check": 503, "/webhooks": 503, "/jurisdictions": 429, "/codes/batch": 429}
+	tests := []struct {
+		name     string
+		call     func(ctx context.Context, c *client.Client) error
+		attempts int
+	}{
+		{"GET after 503", func(ctx context.Context, c *client.Client) error {
+			_, err := c.Health(ctx)
+			return err
+		}, 3},
+		{"GET after 429", func(ctx context.Context, c *client.Client) error {
+			_, err := c.ListJurisdictions(ctx)
+			return err
+		}, 3},
+		{"POST after 503", func(ctx context.Context, c *client.Client) error {
+			_, err := c.CheckPriorAuth(ctx, client.PriorAuthRequest{ProcedureCodes: []string{"E0601"}})
+			return err
+		}, 1},
+		{"POST after 429", func(ctx context.Context, c *client.Client) error {
+			_, err := c.BatchLookup(ctx, client.BatchLookupRequest{Codes: []string{"76942"}})
+			return err
+		}, 3},
+		{"DELETE after 503", func(ctx context.Context, c *client.Client) error {
+			_, err := c.DeleteWebhook(ctx, "wh_1")
+			return err
+		}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, log := mockAPI(t, mock.Options{Failures: failing})
+			err := tt.call(context.Background(), c)
+			var apiErr *client.APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != log.last() {
+				t.Errorf("error = %v, want the last response's APIError", err)
+			}
+			if got := log.count(); got != tt.attempts {
+				t.Errorf("%d attempts, want %d", got, tt.attempts)
+			}
+		})
+	}
+}
+
+// TestRetryPastDeadline checks that a retry that could not start before the
+// context's deadline is skipped, so the caller gets the API's error rather
+// than a timeout.
+func TestRetryPastDeadline(t *testing.T) {
+	c, log := mockAPI(t, mock.Options{RateLimit: 1})
+	c.Retry.MaxDelay = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := c.Health(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// The mock asks for a wait until its minute is up.
+	start := time.Now()
+	_, err := c.Health(ctx)
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("error = %v, want the 429 APIError", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %s, want no wait", elapsed)
+	}
+	if got := log.count(); got != 2 {
+		t.Errorf("%d requests, want 2", got)
+	}
+}
+
+func TestPager(t *testing.T) {
+	c, log := mockAPI(t, mock.Options{})
+	pager := c.ListPoliciesPages(&client.ListPoliciesOptions{Limit: 3})
+
+	var ids []string
+	for pager.Next(context.Background()) {
+		for _, p := range pager.Page().Data {
+			ids = append(ids, p.PolicyID)
+		}
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatal(err)
+	}
+	total := pager.Info().Total
+	if len(ids) != total || pager.Pages() != (total+2)/3 || log.count() != pager.Pages() {
+		t.Errorf("walked %d policies in %d pages and %d requests, want all %d in pages of 3", len(ids), pager.Pages(), log.count(), total)
+	}
+	if pager.More() {
+		t.Error("More = true after the last page")
+	}
+	if pager.Next(context.Background()) {
+		t.Error("Next = true after the last page")
+	}
+}
+
+func TestCache(t *testing.T) {
+	c, log := mockAPI(t, mock.Options{})
+	c.Cache = client.NewCache(t.TempDir())
+	ctx := context.Background()
+
+	calls := []struct {
+		name string
+		call func() error
+		sent bool
+	}{
+		{"first GET", func() error { _, err := c.ListJurisdictions(ctx); return err }, true},
+		{"repeated GET", func() error { _, err := c.ListJurisdictions(ctx); return err }, false},
+		{"first batch", func() error {
+			_, err := c.BatchLookup(ctx, client.BatchLookupRequest{Codes: []string{"76942"}})
+			return err
+		}, true},
+		{"repeated batch", func() error {
+			_, err := c.BatchLookup(ctx, client.BatchLookupRequest{Codes: []string{"76942"}})
+			return err
+		}, false},
+		{"batch of other codes", func() error {
+			_, err := c.BatchLookup(ctx, client.BatchLookupRequest{Codes: []string{"E0601"}})
+			return err
+		}, true},
+		{"uncached POST", func() error {
+			_, err := c.CheckPriorAuth(ctx, client.PriorAuthRequest{ProcedureCodes: []string{"E0601"}})
+			return err
+		}, true},
+		{"repeated uncached POST", func() error {
+			_, err := c.CheckPriorAuth(ctx, client.PriorAuthRequest{ProcedureCodes: []string{"E0601"}})
+			return err
+		}, true},
+		{"another API key", func() error {
+			other := *c
+			other.APIKey = "other"
+			_, err := other.ListJurisdictions(ctx)
+			return err
+		}, true},
+		{"refresh", func() error {
+			c.Cache.Refresh = true
+			defer func() { c.Cache.Refresh = false }()
+			_, err := c.ListJurisdictions(ctx)
+			return err
+		}, true},
+	}
+	for _, call := range calls {
+		before := log.count()
+		if err := call.call(); err != nil {
+			t.Fatalf("%s: %v", call.name, err)
+		}
+		if sent := log.count() > before; sent != call.sent {
+			t.Errorf("%s: sent = %t, want %t", call.name, sent, call.sent)
+		}
+	}
+}
+
+func TestConditionalRequests(t *testing.T) {
+	c, log := mockAPI(t, mock.Options{})
+	c.Validators = client.NewValidators(t.TempDir())
+	ctx := context.Background()
+
+	first, err := c.GetPolicy(ctx, "L33831", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if log.last() != http.StatusOK {
+		t.Fatalf("first request answered %d, want 200", log.last())
+	}
+
+	second, err := c.GetPolicy(ctx, "L33831", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if log.last() != http.StatusNotModified {
+		t.Errorf("repeated request answered %d, want 304", log.last())
+	}
+	if second.Data.PolicyID != "L33831" || second.Data.Title != first.Data.Title {
+		t.Errorf("304 decoded to %+v, want the stored policy %+v", second.Data, first.Data)
+	}
+
+	other := *c
+	other.APIKey = "other"
+	if _, err := other.GetPolicy(ctx, "L33831", nil); err != nil {
+		t.Fatal(err)
+	}
+	if log.last() != http.StatusOK {
+		t.Errorf("request with another API key answered %d, want 200", log.last())
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"strings"
+)
+
+// CodeLookup is the result of looking up a single medical code.
+type CodeLookup struct {
+	Code        string   `json:"code"`
+	CodeSystem  string   `json:"code_system"`
+	Found       bool     `json:"found"`
+	Description string   `json:"description,omitempty"`
+	RVU         *RVU     `json:"rvu,omitempty"`
+	Policies    []Policy `json:"policies,omitempty"`
+}
+
+// RVU holds Medicare Physician Fee Schedule values for a code.
+type RVU struct {
+	WorkRVU          Decimal `json:"work_rvu,omitempty"`
+	NonFacilityPrice Decimal `json:"non_facility_price,omitempty"`
+	FacilityPrice    Decimal `json:"facility_price,omitempty"`
+}
+
+// LookupOptions controls optional parts of a code lookup.
+type LookupOptions struct {
+	Include      []string
+	Jurisdiction string
+	// Exact disables fuzzy matching.
+	Exact bool
+}
+
+// BatchLookupRequest is the body of a batch code lookup.
+type BatchLookupRequest struct {
+	Codes      []string
+	CodeSystem string
+	Include    []string
+}
+
+// LookupCode looks up a medical code (CPT, HCPCS, ICD-10, NDC).
+func (c *Client) LookupCode(ctx context.Context, code string, opts *LookupOptions) (*Response[CodeLookup], error) {
+	if opts == nil {
+		opts = &LookupOptions{}
+	}
+
+	q := NewQuery().
+		Set("code", code).
+		Join("include", opts.Include).
+		Set("jurisdiction", opts.Jurisdiction)
+	if opts.Exact {
+		q.SetBool("fuzzy", false)
+	}
+	path := withQuery(endpoint("codes", "lookup"), q)
+
+	var result Response[CodeLookup]
+	if err := c.Get(ctx, path, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// BatchLookup looks up several codes in a single request.
+func (c *Client) BatchLookup(ctx context.Context, req BatchLookupRequest) (*Response[[]CodeLookup], error) {
+	body := map[string]interface{}{
+		"codes": req.Codes,
+	}
+	if req.CodeSystem != "" {
+		body["code_system"] = req.CodeSystem
+	}
+	if len(req.Include) > 0 {
+		body["include"] = strings.Join(req.Include, ",")
+	}
+
+	var result Response[[]CodeLookup]
+	if err := c.Post(ctx, "/codes/batch", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
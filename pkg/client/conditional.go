@@ -0,0 +1,151 @@
+package client
+
+import (
+	"net/http"
+	"os"
+	"sort"
+	"time"
+)
+
+// Validators remembers GET responses that carried an ETag or Last-Modified
+// header, so later requests for the same URL can be sent with
+// If-None-Match or If-Modified-Since. A 304 Not Modified reply is then
+// answered from the stored body.
+type Validators struct {
+	Dir string
+	// MaxEntries bounds the store. Past it, the least recently used
+	// entries are removed. Zero means no limit.
+	MaxEntries int
+	// MaxAge is how long an entry may go unused before Prune removes it.
+	// Zero means entries never go stale.
+	MaxAge time.Duration
+}
+
+// DefaultMaxValidators is the MaxEntries of a new Validators.
+const DefaultMaxValidators = 200
+
+// DefaultValidatorMaxAge is the MaxAge of a new Validators.
+const DefaultValidatorMaxAge = 30 * 24 * time.Hour
+
+// NewValidators returns a store in dir that keeps up to
+// DefaultMaxValidators responses.
+func NewValidators(dir string) *Validators {
+	return &Validators{Dir: dir, MaxEntries: DefaultMaxValidators, MaxAge: DefaultValidatorMaxAge}
+}
+
+// lookup returns the response stored for url under credential, if there
+// is one.
+func (v *Validators) lookup(credential, url string) *cacheEntry {
+	entry, err := v.store().read(v.store().file(credential, http.MethodGet, url, nil))
+	if err != nil || (entry.ETag == "" && entry.LastModified == "") {
+		return nil
+	}
+	return entry
+}
+
+// conditionalHeader returns the conditional headers for a request revalidating entry.
+func (entry *cacheEntry) conditionalHeader() http.Header {
+	h := http.Header{}
+	if entry.ETag != "" {
+		h.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		h.Set("If-Modified-Since", entry.LastModified)
+	}
+	return h
+}
+
+// save stores body when resp carries a validator, then trims the store to
+// MaxEntries.
+func (v *Validators) save(credential, url string, resp *http.Response, body []byte) error {
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return nil
+	}
+	err := v.store().write(v.store().file(credential, http.MethodGet, url, nil), &cacheEntry{
+		Method:       http.MethodGet,
+		URL:          url,
+		StoredAt:     time.Now(),
+		ETag:         etag,
+		LastModified: lastModified,
+		Body:         body,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = v.trim()
+	return err
+}
+
+// touch marks the entry for url as recently used.
+func (v *Validators) touch(credential, url string) {
+	now := time.Now()
+	os.Chtimes(v.store().file(credential, http.MethodGet, url, nil), now, now)
+}
+
+// Stats reports how many responses the store holds and how much space they
+// use.
+func (v *Validators) Stats() (CacheStats, error) {
+	return v.store().stats()
+}
+
+// Clear removes every stored response and returns how many there were.
+func (v *Validators) Clear() (int, error) {
+	return v.store().remove(func(*cacheEntry) bool { return true })
+}
+
+// Prune removes unreadable entries, entries unused for MaxAge and the
+// least recently used entries beyond MaxEntries, and returns how many it
+// removed.
+func (v *Validators) Prune() (int, error) {
+	cutoff := time.Now().Add(-v.MaxAge)
+	removed := 0
+	err := v.store().walk(func(path string, info os.FileInfo) error {
+		if _, err := v.store().read(path); err == nil && (v.MaxAge <= 0 || info.ModTime().After(cutoff)) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		removed++
+		return nil
+	})
+	if err != nil {
+		return removed, err
+	}
+	trimmed, err := v.trim()
+	return removed + trimmed, err
+}
+
+// trim removes the least recently used entries beyond MaxEntries and
+// returns how many it removed.
+func (v *Validators) trim() (int, error) {
+	if v.MaxEntries <= 0 {
+		return 0, nil
+	}
+	type file struct {
+		path string
+		used time.Time
+	}
+	var files []file
+	err := v.store().walk(func(path string, info os.FileInfo) error {
+		files = append(files, file{path: path, used: info.ModTime()})
+		return nil
+	})
+	if err != nil || len(files) <= v.MaxEntries {
+		return 0, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].used.After(files[j].used)
+	})
+	for _, f := range files[v.MaxEntries:] {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+	}
+	return len(files) - v.MaxEntries, nil
+}
+
+func (v *Validators) store() diskStore {
+	return diskStore{dir: v.Dir}
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+// storeValidated saves a response carrying an ETag for each URL, the first
+// one used longest ago.
+func storeValidated(t *testing.T, v *Validators, urls ...string) {
+	t.Helper()
+	resp := &http.Response{Header: http.Header{"Etag": {`"v1"`}}}
+	for i, url := range urls {
+		if err := v.save("test", url, resp, []byte(`{}`)); err != nil {
+			t.Fatal(err)
+		}
+		used := time.Now().Add(time.Duration(i-len(urls)) * time.Hour)
+		if err := os.Chtimes(v.store().file("test", http.MethodGet, url, nil), used, used); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestValidatorsSave(t *testing.T) {
+	v := NewValidators(t.TempDir())
+	url := "https://verity.example/api/v1/policies/L33831"
+
+	if err := v.save("test", url, &http.Response{Header: http.Header{}}, []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	if v.lookup("test", url) != nil {
+		t.Error("a response without ETag or Last-Modified was stored")
+	}
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+	if err := v.save("test", url, resp, []byte(`{"success": true}`)); err != nil {
+		t.Fatal(err)
+	}
+	if got := v.lookup("test", url).conditionalHeader().Get("If-Modified-Since"); got != "Mon, 02 Jan 2006 15:04:05 GMT" {
+		t.Errorf("If-Modified-Since = %q, want the stored Last-Modified", got)
+	}
+	if v.lookup("other", url) != nil {
+		t.Error("lookup with another API key found the response")
+	}
+}
+
+func TestValidatorsTrim(t *testing.T) {
+	v := NewValidators(t.TempDir())
+	v.MaxEntries = 2
+	var urls []string
+	for i := 0; i < 3; i++ {
+		urls = append(urls, fmt.Sprintf("https://verity.example/api/v1/policies/L%d", i))
+	}
+	storeValidated(t, v, urls...)
+
+	if v.lookup("test", urls[0]) != nil {
+		t.Error("the least recently used response was kept")
+	}
+	for _, url := range urls[1:] {
+		if v.lookup("test", url) == nil {
+			t.Errorf("%s was removed", url)
+		}
+	}
+}
+
+func TestValidatorsPrune(t *testing.T) {
+	v := NewValidators(t.TempDir())
+	v.MaxAge = 90 * time.Minute
+	stale := "https://verity.example/api/v1/policies/L0"
+	recent := "https://verity.example/api/v1/policies/L2"
+	storeValidated(t, v, stale, "https://verity.example/api/v1/policies/L1", recent)
+	if err := os.WriteFile(v.store().file("test", http.MethodGet, "broken", nil), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// L0 was last used three hours ago and L1 two, so both are past MaxAge.
+	// The unreadable entry goes too.
+	removed, err := v.Prune()
+	if err != nil || removed != 3 {
+		t.Fatalf("Prune = %d, %v; want 3 removed", removed, err)
+	}
+	if v.lookup("test", stale) != nil {
+		t.Error("a response unused for longer than MaxAge was kept")
+	}
+	if v.lookup("test", recent) == nil {
+		t.Error("a recently used response was removed")
+	}
+}
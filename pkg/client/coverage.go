@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+)
+
+// CriteriaBlock is one section of coverage criteria text from a policy.
+type CriteriaBlock struct {
+	PolicyID    string `json:"policy_id,omitempty"`
+	PolicyTitle string `json:"policy_title,omitempty"`
+	Section     string `json:"section,omitempty"`
+	Text        string `json:"text"`
+}
+
+// CriteriaSearchOptions filters a coverage criteria search.
+type CriteriaSearchOptions struct {
+	Section      string
+	PolicyType   string
+	Jurisdiction string
+	// Limit is the page size, the API's default when zero. Page and Cursor
+	// select a page other than the first.
+	Limit  int
+	Page   int
+	Cursor string
+}
+
+// EvaluateRequest describes a patient scenario to evaluate against a policy.
+type EvaluateRequest struct {
+	PolicyID       string   `json:"policy_id"`
+	Age            int      `json:"age,omitempty"`
+	Gender         string   `json:"gender,omitempty"`
+	DiagnosisCodes []string `json:"diagnosis_codes,omitempty"`
+	ProcedureCode  string   `json:"procedure_code,omitempty"`
+	Modifier       string   `json:"modifier,omitempty"`
+	PlaceOfService string   `json:"place_of_service,omitempty"`
+}
+
+// EvaluateResult is the coverage verdict for an EvaluateRequest.
+type EvaluateResult struct {
+	Covered         bool            `json:"covered"`
+	Confidence      string          `json:"confidence,omitempty"`
+	Reasons         []string        `json:"reasons,omitempty"`
+	PolicyID        string          `json:"policy_id,omitempty"`
+	MatchedCriteria []CriteriaBlock `json:"matched_criteria,omitempty"`
+}
+
+// SearchCriteria searches coverage criteria text across all policies for
+// query. opts may be nil.
+func (c *Client) SearchCriteria(ctx context.Context, query string, opts *CriteriaSearchOptions) (*Response[[]CriteriaBlock], error) {
+	var result Response[[]CriteriaBlock]
+	if err := c.Get(ctx, criteriaSearchPath(query, opts), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SearchCriteriaPages walks every page of a criteria search.
+func (c *Client) SearchCriteriaPages(query string, opts *CriteriaSearchOptions) *Pager[CriteriaBlock] {
+	return newPager[CriteriaBlock](c, criteriaSearchPath(query, opts))
+}
+
+func criteriaSearchPath(query string, opts *CriteriaSearchOptions) string {
+	if opts == nil {
+		opts = &CriteriaSearchOptions{}
+	}
+
+	q := NewQuery().
+		Set("q", query).
+		Set("section", opts.Section).
+		Set("policy_type", opts.PolicyType).
+		Set("jurisdiction", opts.Jurisdiction).
+		SetInt("limit", opts.Limit).
+		SetInt("page", opts.Page).
+		Set("cursor", opts.Cursor)
+	return withQuery(endpoint("coverage", "criteria"), q)
+}
+
+// EvaluateCoverage evaluates whether a procedure is covered under a policy.
+func (c *Client) EvaluateCoverage(ctx context.Context, req EvaluateRequest) (*Response[EvaluateResult], error) {
+	var result Response[EvaluateResult]
+	if err := c.Post(ctx, "/coverage/evaluate", req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
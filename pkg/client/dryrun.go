@@ -0,0 +1,102 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrDryRun is returned for every request made through a DryRun transport.
+// It is never retried.
+var ErrDryRun = errors.New("dry run: request not sent")
+
+// DryRun is an http.RoundTripper that prints each request instead of
+// sending it, and fails it with ErrDryRun. Requests are built by the
+// client exactly as they would be for the real API, so what is printed is
+// what would have been sent.
+type DryRun struct {
+	Out io.Writer
+	// Curl prints an equivalent curl command instead of the method, URL
+	// and body. The API key is written as $VERITY_API_KEY.
+	Curl bool
+
+	mu sync.Mutex
+}
+
+// NewDryRun returns a transport printing requests to out.
+func NewDryRun(out io.Writer, curl bool) *DryRun {
+	return &DryRun{Out: out, Curl: curl}
+}
+
+// RoundTrip prints req and returns ErrDryRun.
+func (d *DryRun) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	var text string
+	if d.Curl {
+		text = CurlCommand(req, body)
+	} else {
+		text = req.Method + " " + req.URL.String() + "\n"
+		if len(body) > 0 {
+			var indented bytes.Buffer
+			if json.Indent(&indented, body, "", "  ") == nil {
+				body = indented.Bytes()
+			}
+			text += "\n" + strings.TrimRight(string(body), "\n") + "\n"
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	fmt.Fprint(d.Out, text)
+	return nil, ErrDryRun
+}
+
+// CurlCommand returns a shell command that sends req with curl. The
+// Authorization header refers to $VERITY_API_KEY rather than containing
+// the key.
+func CurlCommand(req *http.Request, body []byte) string {
+	var b strings.Builder
+	b.WriteString("curl")
+	if req.Method != http.MethodGet || len(body) > 0 {
+		b.WriteString(" -X " + req.Method)
+	}
+	b.WriteString(" " + shellQuote(req.URL.String()))
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			if http.CanonicalHeaderKey(name) == "Authorization" {
+				b.WriteString(" \\\n  -H \"Authorization: Bearer $VERITY_API_KEY\"")
+				continue
+			}
+			b.WriteString(" \\\n  -H " + shellQuote(name+": "+value))
+		}
+	}
+	if len(body) > 0 {
+		b.WriteString(" \\\n  --data-raw " + shellQuote(string(body)))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned for every non-success response from the API. Use
+// errors.As to inspect it.
+type APIError struct {
+	StatusCode int                    `json:"status"`
+	Code       string                 `json:"code,omitempty"`
+	Message    string                 `json:"message"`
+	Hint       string                 `json:"hint,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	RequestID  string                 `json:"request_id,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Message)
+	}
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// newAPIError builds an APIError from a failed response, falling back to
+// the raw body when it is not the API's error envelope.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && !errResp.Success && errResp.Error.Message != "" {
+		apiErr.Code = errResp.Error.Code
+		apiErr.Message = errResp.Error.Message
+		apiErr.Hint = errResp.Error.Hint
+		apiErr.Details = errResp.Error.Details
+		if errResp.Error.RequestID != "" {
+			apiErr.RequestID = errResp.Error.RequestID
+		}
+		return apiErr
+	}
+
+	apiErr.Message = strings.TrimSpace(string(body))
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
@@ -0,0 +1,27 @@
+package client
+
+import "context"
+
+// HealthStatus is the API health report.
+type HealthStatus struct {
+	Status    string                 `json:"status"`
+	Version   string                 `json:"version,omitempty"`
+	Timestamp string                 `json:"timestamp,omitempty"`
+	Checks    map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+// HealthCheck is the status of one dependency such as the database.
+type HealthCheck struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms,omitempty"`
+	Message   string  `json:"message,omitempty"`
+}
+
+// Health reports the health of the API and its dependencies.
+func (c *Client) Health(ctx context.Context) (*Response[HealthStatus], error) {
+	var result Response[HealthStatus]
+	if err := c.Get(ctx, "/health", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,19 @@
+package client
+
+import "context"
+
+// Jurisdiction is a Medicare Administrative Contractor (MAC) jurisdiction.
+type Jurisdiction struct {
+	JurisdictionCode string   `json:"jurisdiction_code"`
+	MacName          string   `json:"mac_name"`
+	States           []string `json:"states,omitempty"`
+}
+
+// ListJurisdictions lists all MAC jurisdictions.
+func (c *Client) ListJurisdictions(ctx context.Context) (*Response[[]Jurisdiction], error) {
+	var result Response[[]Jurisdiction]
+	if err := c.Get(ctx, "/jurisdictions", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,250 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Pager walks the pages of a list endpoint. It follows whichever
+// continuation the API returns in meta: a cursor, a next-page URL, or page
+// numbers or offsets with a total or has_more flag.
+//
+//	pager := c.ListPoliciesPages(opts)
+//	for pager.Next(ctx) {
+//		for _, p := range pager.Page().Data { ... }
+//	}
+//	if err := pager.Err(); err != nil { ... }
+type Pager[T any] struct {
+	client *Client
+	next   string
+	page   *Response[[]T]
+	info   PageInfo
+	pages  int
+	err    error
+}
+
+// PageInfo is what a list response's meta says about its position in the
+// result set. Fields the API did not send are zero.
+type PageInfo struct {
+	Page       int    `json:"page"`
+	PerPage    int    `json:"per_page"`
+	Limit      int    `json:"limit"`
+	Offset     *int   `json:"offset"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	HasMore    *bool  `json:"has_more"`
+	NextCursor string `json:"next_cursor"`
+	Next       string `json:"next"`
+	NextURL    string `json:"next_url"`
+}
+
+func newPager[T any](c *Client, path string) *Pager[T] {
+	return &Pager[T]{client: c, next: path}
+}
+
+// Next fetches the next page. It returns false when there are no more
+// pages or a request failed; check Err afterwards.
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.err != nil || p.next == "" {
+		return false
+	}
+
+	var page Response[[]T]
+	if err := p.client.Get(ctx, p.next, &page); err != nil {
+		p.err = err
+		return false
+	}
+
+	p.page = &page
+	p.pages++
+	p.info = parsePageInfo(page.Meta)
+	if len(page.Data) == 0 {
+		// An empty page ends the walk whatever meta says, so a
+		// misbehaving server cannot loop us forever.
+		p.next = ""
+	} else {
+		p.next, p.err = p.client.nextPagePath(p.next, p.info, len(page.Data))
+	}
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *Pager[T]) Page() *Response[[]T] {
+	return p.page
+}
+
+// Pages returns how many pages have been fetched.
+func (p *Pager[T]) Pages() int {
+	return p.pages
+}
+
+// Info returns the pagination meta of the last page.
+func (p *Pager[T]) Info() PageInfo {
+	return p.info
+}
+
+// More reports whether another page is available.
+func (p *Pager[T]) More() bool {
+	return p.err == nil && p.next != ""
+}
+
+// Err returns the error that stopped the walk, if any.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// JoinPages combines the pages of a list into one response. When every
+// page was decoded from the API, the result's Raw holds all their records
+// as sent, so nothing the models leave out is lost.
+func JoinPages[T any](pages []*Response[[]T]) *Response[[]T] {
+	joined := &Response[[]T]{Success: len(pages) > 0}
+	var records []json.RawMessage
+	raw := true
+	for _, p := range pages {
+		joined.Success = joined.Success && p.Success
+		joined.Data = append(joined.Data, p.Data...)
+		r, ok := p.RawRecords()
+		raw = raw && ok
+		records = append(records, r...)
+	}
+	if raw && len(pages) > 0 {
+		joined.Raw, _ = json.Marshal(struct {
+			Success bool              `json:"success"`
+			Data    []json.RawMessage `json:"data"`
+		}{joined.Success, records})
+	}
+	return joined
+}
+
+// parsePageInfo reads pagination fields from meta, either at its top level
+// or in a nested "pagination" object.
+func parsePageInfo(meta json.RawMessage) PageInfo {
+	var info PageInfo
+	if len(meta) == 0 {
+		return info
+	}
+	var nested struct {
+		Pagination *PageInfo `json:"pagination"`
+	}
+	if json.Unmarshal(meta, &nested) == nil && nested.Pagination != nil {
+		return *nested.Pagination
+	}
+	json.Unmarshal(meta, &info)
+	return info
+}
+
+// nextPagePath returns the request path for the page after the one fetched
+// from path, which held count records, or "" when it was the last.
+func (c *Client) nextPagePath(path string, info PageInfo, count int) (string, error) {
+	if info.NextCursor != "" {
+		return setQuery(path, "cursor", info.NextCursor)
+	}
+
+	if next := firstNonEmpty(info.Next, info.NextURL); next != "" {
+		return c.relativePath(next)
+	}
+
+	if info.Offset != nil {
+		next := *info.Offset + count
+		more := info.Total > 0 && next < info.Total
+		if info.HasMore != nil {
+			more = *info.HasMore
+		}
+		if !more {
+			return "", nil
+		}
+		return setQuery(path, "offset", strconv.Itoa(next))
+	}
+
+	// Page numbers: trust meta, falling back to what was requested.
+	current := firstNonZero(info.Page, queryInt(path, "page"), 1)
+	perPage := firstNonZero(info.PerPage, info.Limit, queryInt(path, "limit"))
+	more := false
+	switch {
+	case info.HasMore != nil:
+		more = *info.HasMore
+	case info.TotalPages > 0:
+		more = current < info.TotalPages
+	case info.Total > 0 && perPage > 0:
+		more = current*perPage < info.Total
+	}
+	if !more {
+		return "", nil
+	}
+	return setQuery(path, "page", strconv.Itoa(current+1))
+}
+
+// relativePath turns a next-page link into a path under BaseURL. Links to
+// any other host are refused so the API key is never sent elsewhere.
+func (c *Client) relativePath(link string) (string, error) {
+	base, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid next page link %q: %w", link, err)
+	}
+	abs := base.ResolveReference(ref).String()
+
+	prefix := strings.TrimSuffix(c.BaseURL, "/")
+	if !strings.HasPrefix(abs, prefix+"/") {
+		return "", fmt.Errorf("next page link %q is outside %s", link, c.BaseURL)
+	}
+	return strings.TrimPrefix(abs, prefix), nil
+}
+
+// setQuery returns path with the query parameter key set to value.
+func setQuery(path, key, value string) (string, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set(key, value)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// defaultPageSize is used by list endpoints that always send a limit.
+const defaultPageSize = 50
+
+// pageQuery starts a list query with the page size and position.
+func pageQuery(limit, page int, cursor string) *Query {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	return NewQuery().SetInt("limit", limit).SetInt("page", page).Set("cursor", cursor)
+}
+
+// queryInt returns the integer query parameter key of path, or 0.
+func queryInt(path, key string) int {
+	u, err := url.Parse(path)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(u.Query().Get(key))
+	return n
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func firstNonZero(values ...int) int {
+	for _, v := range values {
+		if v != 0 {
+			return v
+		}
+	}
+	return 0
+}
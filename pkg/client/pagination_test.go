@@ -0,0 +1,65 @@
+package client
+
+import "testing"
+
+func TestNextPagePath(t *testing.T) {
+	yes, no := true, false
+	zero, forty := 0, 40
+	c := &Client{BaseURL: "https://verity.example/api/v1"}
+
+	tests := []struct {
+		name  string
+		path  string
+		info  PageInfo
+		count int
+		want  string
+	}{
+		{"cursor", "/policies?limit=2", PageInfo{NextCursor: "abc"}, 2, "/policies?cursor=abc&limit=2"},
+		{"next link", "/policies", PageInfo{Next: "/api/v1/policies?page=2"}, 2, "/policies?page=2"},
+		{"absolute next_url", "/policies", PageInfo{NextURL: "https://verity.example/api/v1/policies?page=2"}, 2, "/policies?page=2"},
+		{"offset with total", "/policies?offset=0", PageInfo{Offset: &zero, Total: 5}, 2, "/policies?offset=2"},
+		{"offset at total", "/policies?offset=40", PageInfo{Offset: &forty, Total: 42}, 2, ""},
+		{"offset with has_more", "/policies", PageInfo{Offset: &forty, HasMore: &yes}, 10, "/policies?offset=50"},
+		{"has_more", "/policies?page=1", PageInfo{Page: 1, HasMore: &yes}, 2, "/policies?page=2"},
+		{"no more", "/policies?page=1", PageInfo{Page: 1, HasMore: &no, TotalPages: 9}, 2, ""},
+		{"total_pages", "/policies", PageInfo{Page: 2, TotalPages: 3}, 2, "/policies?page=3"},
+		{"last of total_pages", "/policies", PageInfo{Page: 3, TotalPages: 3}, 2, ""},
+		{"total and per_page", "/policies", PageInfo{Page: 1, PerPage: 2, Total: 3}, 2, "/policies?page=2"},
+		{"total and requested limit", "/policies?limit=2&page=2", PageInfo{Total: 3}, 1, ""},
+		{"no meta", "/policies", PageInfo{}, 2, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.nextPagePath(tt.path, tt.info, tt.count)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("nextPagePath = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelativePath(t *testing.T) {
+	c := &Client{BaseURL: "https://verity.example/api/v1"}
+	tests := []struct {
+		link    string
+		want    string
+		wantErr bool
+	}{
+		{"/api/v1/policies?page=2", "/policies?page=2", false},
+		{"https://verity.example/api/v1/policies?page=2", "/policies?page=2", false},
+		{"https://elsewhere.example/api/v1/policies?page=2", "", true},
+		{"http://verity.example/api/v1/policies", "", true},
+		{"/api/v10/policies", "", true},
+		{"/other/policies", "", true},
+		{"%zz", "", true},
+	}
+	for _, tt := range tests {
+		got, err := c.relativePath(tt.link)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("relativePath(%q) = %q, %v; want %q, error %t", tt.link, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
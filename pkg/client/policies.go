@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"strings"
+)
+
+// Policy is a Medicare coverage policy (LCD, Article or NCD). Lists return
+// the summary fields only; the detail endpoint fills in whatever was
+// requested through include.
+type Policy struct {
+	PolicyID      string          `json:"policy_id"`
+	Title         string          `json:"title"`
+	PolicyType    string          `json:"policy_type"`
+	Jurisdiction  string          `json:"jurisdiction,omitempty"`
+	Status        string          `json:"status,omitempty"`
+	Disposition   string          `json:"disposition,omitempty"`
+	EffectiveDate string          `json:"effective_date,omitempty"`
+	Description   string          `json:"description,omitempty"`
+	Summary       string          `json:"summary,omitempty"`
+	Criteria      []CriteriaBlock `json:"criteria,omitempty"`
+	Codes         []PolicyCode    `json:"codes,omitempty"`
+	Attachments   []Attachment    `json:"attachments,omitempty"`
+	Versions      []PolicyVersion `json:"versions,omitempty"`
+}
+
+// PolicyCode is a code referenced by a policy.
+type PolicyCode struct {
+	Code        string `json:"code"`
+	CodeSystem  string `json:"code_system,omitempty"`
+	Description string `json:"description,omitempty"`
+	Disposition string `json:"disposition,omitempty"`
+}
+
+// Attachment is a document attached to a policy.
+type Attachment struct {
+	Title string `json:"title,omitempty"`
+	URL   string `json:"url,omitempty"`
+	Type  string `json:"type,omitempty"`
+}
+
+// PolicyVersion is one historical revision of a policy.
+type PolicyVersion struct {
+	Version       string `json:"version,omitempty"`
+	EffectiveDate string `json:"effective_date,omitempty"`
+	RetiredDate   string `json:"retired_date,omitempty"`
+	Summary       string `json:"summary,omitempty"`
+}
+
+// PolicyChange is one entry of the policy change feed.
+type PolicyChange struct {
+	PolicyID      string `json:"policy_id"`
+	ChangeType    string `json:"change_type"`
+	ChangeSummary string `json:"change_summary,omitempty"`
+	Timestamp     string `json:"timestamp,omitempty"`
+}
+
+// PolicyComparison groups the policies for a set of procedures by
+// jurisdiction.
+type PolicyComparison struct {
+	Comparison []JurisdictionPolicies `json:"comparison"`
+}
+
+// JurisdictionPolicies lists the policies that apply in one jurisdiction.
+type JurisdictionPolicies struct {
+	Jurisdiction string   `json:"jurisdiction"`
+	MacName      string   `json:"mac_name,omitempty"`
+	Policies     []Policy `json:"policies"`
+}
+
+// ListPoliciesOptions filters a policy search.
+type ListPoliciesOptions struct {
+	Query        string
+	Mode         string
+	PolicyType   string
+	Jurisdiction string
+	Status       string
+	ICD10        string
+	// Limit is the page size, 50 when zero. Page and Cursor select a page
+	// other than the first.
+	Limit  int
+	Page   int
+	Cursor string
+}
+
+// GetPolicyOptions selects the optional sections of a policy.
+type GetPolicyOptions struct {
+	// Include names sections to add: criteria, codes, attachments and
+	// versions.
+	Include []string
+}
+
+// PolicyChangesOptions filters the policy change feed.
+type PolicyChangesOptions struct {
+	Since      string
+	PolicyID   string
+	ChangeType string
+	// Limit is the page size, 50 when zero. Page and Cursor select a page
+	// other than the first.
+	Limit  int
+	Page   int
+	Cursor string
+}
+
+// ComparePoliciesRequest is the body of a cross-jurisdiction comparison.
+type ComparePoliciesRequest struct {
+	ProcedureCodes []string `json:"procedure_codes"`
+	PolicyType     string   `json:"policy_type,omitempty"`
+	Jurisdictions  []string `json:"jurisdictions,omitempty"`
+}
+
+// mcdBaseURL is the CMS Medicare Coverage Database, which hosts the public
+// text of every LCD and article.
+const mcdBaseURL = "https://www.cms.gov/medicare-coverage-database/view/"
+
+// PolicyURL returns the Medicare Coverage Database page for an LCD (L33831)
+// or article (A52) ID, or "" for IDs it cannot map, such as NCDs.
+func PolicyURL(policyID string) string {
+	if len(policyID) < 2 {
+		return ""
+	}
+	num := policyID[1:]
+	if strings.Trim(num, "0123456789") != "" {
+		return ""
+	}
+	switch policyID[0] {
+	case 'L':
+		return mcdBaseURL + "lcd.aspx?lcdid=" + num
+	case 'A':
+		return mcdBaseURL + "article.aspx?articleid=" + num
+	}
+	return ""
+}
+
+// ListPolicies searches coverage policies.
+func (c *Client) ListPolicies(ctx context.Context, opts *ListPoliciesOptions) (*Response[[]Policy], error) {
+	var result Response[[]Policy]
+	if err := c.Get(ctx, listPoliciesPath(opts), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListPoliciesPages walks every page of a policy search.
+func (c *Client) ListPoliciesPages(opts *ListPoliciesOptions) *Pager[Policy] {
+	return newPager[Policy](c, listPoliciesPath(opts))
+}
+
+func listPoliciesPath(opts *ListPoliciesOptions) string {
+	if opts == nil {
+		opts = &ListPoliciesOptions{}
+	}
+
+	q := pageQuery(opts.Limit, opts.Page, opts.Cursor).
+		Set("q", opts.Query).
+		Set("mode", opts.Mode).
+		Set("policy_type", opts.PolicyType).
+		Set("jurisdiction", opts.Jurisdiction).
+		Set("status", opts.Status).
+		Set("icd10", opts.ICD10)
+	return withQuery(endpoint("policies"), q)
+}
+
+// GetPolicy fetches a single policy. opts may be nil.
+func (c *Client) GetPolicy(ctx context.Context, policyID string, opts *GetPolicyOptions) (*Response[Policy], error) {
+	if opts == nil {
+		opts = &GetPolicyOptions{}
+	}
+
+	path := withQuery(endpoint("policies", policyID), NewQuery().Join("include", opts.Include))
+
+	var result Response[Policy]
+	if err := c.Get(ctx, path, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListPolicyChanges returns the policy change feed.
+func (c *Client) ListPolicyChanges(ctx context.Context, opts *PolicyChangesOptions) (*Response[[]PolicyChange], error) {
+	var result Response[[]PolicyChange]
+	if err := c.Get(ctx, policyChangesPath(opts), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListPolicyChangesPages walks every page of the policy change feed.
+func (c *Client) ListPolicyChangesPages(opts *PolicyChangesOptions) *Pager[PolicyChange] {
+	return newPager[PolicyChange](c, policyChangesPath(opts))
+}
+
+func policyChangesPath(opts *PolicyChangesOptions) string {
+	if opts == nil {
+		opts = &PolicyChangesOptions{}
+	}
+
+	q := pageQuery(opts.Limit, opts.Page, opts.Cursor).
+		Set("since", opts.Since).
+		Set("policy_id", opts.PolicyID).
+		Set("change_type", opts.ChangeType)
+	return withQuery(endpoint("policies", "changes"), q)
+}
+
+// ComparePolicies compares coverage policies across MAC jurisdictions.
+func (c *Client) ComparePolicies(ctx context.Context, req ComparePoliciesRequest) (*Response[PolicyComparison], error) {
+	var result Response[PolicyComparison]
+	if err := c.Post(ctx, "/policies/compare", req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
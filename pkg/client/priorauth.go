@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+)
+
+// PriorAuthRequest is the body of a prior authorization check.
+type PriorAuthRequest struct {
+	ProcedureCodes  []string `json:"procedure_codes"`
+	DiagnosisCodes  []string `json:"diagnosis_codes,omitempty"`
+	State           string   `json:"state,omitempty"`
+	Payer           string   `json:"payer"`
+	CriteriaPage    int      `json:"criteria_page,omitempty"`
+	CriteriaPerPage int      `json:"criteria_per_page,omitempty"`
+}
+
+// PriorAuthResult is the outcome of a prior authorization check.
+type PriorAuthResult struct {
+	PARequired             bool     `json:"pa_required"`
+	Confidence             string   `json:"confidence,omitempty"`
+	Reason                 string   `json:"reason,omitempty"`
+	MatchedPolicies        []Policy `json:"matched_policies,omitempty"`
+	DocumentationChecklist []string `json:"documentation_checklist,omitempty"`
+}
+
+// ResearchRequest starts an AI-assisted prior authorization research task.
+type ResearchRequest struct {
+	ProcedureCodes  []string `json:"procedure_codes"`
+	Payer           string   `json:"payer,omitempty"`
+	State           string   `json:"state,omitempty"`
+	DiagnosisCodes  []string `json:"diagnosis_codes,omitempty"`
+	ClinicalContext string   `json:"clinical_context,omitempty"`
+	Sync            bool     `json:"sync"`
+}
+
+// ResearchTask is the state of a research task. Result is set once the
+// task has completed.
+type ResearchTask struct {
+	ResearchID string          `json:"research_id"`
+	Status     string          `json:"status"`
+	CreatedAt  string          `json:"created_at,omitempty"`
+	PollURL    string          `json:"poll_url,omitempty"`
+	Result     *ResearchResult `json:"result,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// ResearchResult holds the findings of a completed research task.
+type ResearchResult struct {
+	Determination             *Determination `json:"determination,omitempty"`
+	DocumentationRequirements []string       `json:"documentation_requirements,omitempty"`
+	Sources                   []string       `json:"sources,omitempty"`
+}
+
+// Determination is the research verdict on whether prior auth is needed.
+type Determination struct {
+	PARequired bool   `json:"pa_required"`
+	Confidence string `json:"confidence,omitempty"`
+	Reasoning  string `json:"reasoning,omitempty"`
+}
+
+// CheckPriorAuth checks whether procedures require prior authorization.
+func (c *Client) CheckPriorAuth(ctx context.Context, req PriorAuthRequest) (*Response[PriorAuthResult], error) {
+	var result Response[PriorAuthResult]
+	if err := c.Post(ctx, "/prior-auth/check", req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// StartResearch starts a research task, or runs it to completion when
+// req.Sync is set.
+func (c *Client) StartResearch(ctx context.Context, req ResearchRequest) (*Response[ResearchTask], error) {
+	var result Response[ResearchTask]
+	if err := c.Post(ctx, "/prior-auth/research", req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetResearch returns the current state of a research task.
+func (c *Client) GetResearch(ctx context.Context, researchID string) (*Response[ResearchTask], error) {
+	path := endpoint("prior-auth", "research", researchID)
+
+	var result Response[ResearchTask]
+	if err := c.Get(ctx, path, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
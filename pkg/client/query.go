@@ -0,0 +1,81 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Query builds the query string of a request. Values are escaped when the
+// query is encoded, so search text such as "ultrasound & guidance" reaches
+// the API intact. The setters skip empty values, so optional filters can be
+// added without checking them first.
+type Query struct {
+	values url.Values
+}
+
+// NewQuery returns an empty query.
+func NewQuery() *Query {
+	return &Query{values: url.Values{}}
+}
+
+// Set sets key to value, unless value is empty.
+func (q *Query) Set(key, value string) *Query {
+	if value != "" {
+		q.values.Set(key, value)
+	}
+	return q
+}
+
+// SetInt sets key to n, unless n is zero.
+func (q *Query) SetInt(key string, n int) *Query {
+	if n != 0 {
+		q.values.Set(key, strconv.Itoa(n))
+	}
+	return q
+}
+
+// SetBool sets key to "true" or "false".
+func (q *Query) SetBool(key string, b bool) *Query {
+	q.values.Set(key, strconv.FormatBool(b))
+	return q
+}
+
+// Join sets key to values joined with commas, as in include=criteria,codes.
+func (q *Query) Join(key string, values []string) *Query {
+	return q.Set(key, strings.Join(values, ","))
+}
+
+// Repeat adds key once per value, as in codes=A&codes=B.
+func (q *Query) Repeat(key string, values []string) *Query {
+	for _, v := range values {
+		if v != "" {
+			q.values.Add(key, v)
+		}
+	}
+	return q
+}
+
+// Encode returns the query in URL-encoded form, sorted by key.
+func (q *Query) Encode() string {
+	return q.values.Encode()
+}
+
+// endpoint joins segments into a request path, escaping each one so an ID
+// containing "/", "?" or "#" stays a single path segment.
+func endpoint(segments ...string) string {
+	var b strings.Builder
+	for _, s := range segments {
+		b.WriteByte('/')
+		b.WriteString(url.PathEscape(s))
+	}
+	return b.String()
+}
+
+// withQuery appends q to path.
+func withQuery(path string, q *Query) string {
+	if encoded := q.Encode(); encoded != "" {
+		return path + "?" + encoded
+	}
+	return path
+}
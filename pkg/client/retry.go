@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how transient failures are retried. Delays grow
+// exponentially from BaseDelay up to MaxDelay with random jitter, unless the
+// server asks for a specific delay through Retry-After.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// DefaultRetryPolicy is the policy used by New.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+// RequestOption adjusts how a single request is sent.
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	idempotent bool
+}
+
+// Idempotent marks a request as safe to repeat even though its method is
+// not, such as a read-only lookup sent as a POST.
+func Idempotent() RequestOption {
+	return func(o *requestOptions) {
+		o.idempotent = true
+	}
+}
+
+// isIdempotentMethod reports whether requests with method are safe to
+// repeat. DELETE is left out: a retried delete whose first attempt went
+// through fails with 404 instead of succeeding.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut:
+		return true
+	}
+	return false
+}
+
+// shouldRetry reports whether an attempt that ended with resp or err may be
+// sent again. Requests that are not idempotent are only retried when the
+// server cannot have acted on them: a refused connection or a 429.
+func shouldRetry(ctx context.Context, resp *http.Response, err error, idempotent bool) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		if errors.Is(err, ErrNotRecorded) || errors.Is(err, ErrDryRun) {
+			return false
+		}
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+		return idempotent
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// delay returns how long to wait before retry number attempt (zero based).
+// A Retry-After longer than MaxDelay is cut to MaxDelay.
+func (p RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if p.MaxDelay > 0 && d > p.MaxDelay {
+				d = p.MaxDelay
+			}
+			return d
+		}
+	}
+
+	d := p.BaseDelay << attempt
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	// Jitter into [d/2, d] so a fleet of scripts does not retry in lockstep.
+	return d/2 + rand.N(d/2+1)
+}
+
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{"7", 7 * time.Second, true},
+		{"-1", 0, false},
+		{"1.5", 0, false},
+		{"soon", 0, false},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %s, %t; want %s, %t", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	// An HTTP date is the time left until then, give or take the clock.
+	got, ok := parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	if !ok || got < 58*time.Second || got > time.Minute {
+		t.Errorf("parseRetryAfter(a minute from now) = %s, %t; want about a minute", got, ok)
+	}
+}
+
+func TestDelay(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 30 * time.Second}
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter string
+		min, max   time.Duration
+	}{
+		{"first backoff", 0, "", 50 * time.Millisecond, 100 * time.Millisecond},
+		{"third backoff", 2, "", 200 * time.Millisecond, 400 * time.Millisecond},
+		{"backoff past the cap", 20, "", 15 * time.Second, 30 * time.Second},
+		{"Retry-After", 0, "2", 2 * time.Second, 2 * time.Second},
+		{"Retry-After past the cap", 0, "3600", 30 * time.Second, 30 * time.Second},
+		{"invalid Retry-After", 0, "later", 50 * time.Millisecond, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			if got := p.delay(tt.attempt, resp); got < tt.min || got > tt.max {
+				t.Errorf("delay = %s, want between %s and %s", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	refused := fmt.Errorf("request failed: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")})
+	reset := fmt.Errorf("request failed: %w", &net.OpError{Op: "read", Err: errors.New("connection reset")})
+	status := func(code int) *http.Response { return &http.Response{StatusCode: code} }
+
+	tests := []struct {
+		method string
+		resp   *http.Response
+		err    error
+		want   bool
+	}{
+		{"GET", status(429), nil, true},
+		{"GET", status(502), nil, true},
+		{"GET", status(503), nil, true},
+		{"GET", status(504), nil, true},
+		{"GET", status(500), nil, false},
+		{"GET", status(404), nil, false},
+		{"GET", nil, refused, true},
+		{"GET", nil, reset, true},
+		{"PUT", status(503), nil, true},
+		{"POST", status(429), nil, true},
+		{"POST", status(503), nil, false},
+		{"POST", nil, refused, true},
+		{"POST", nil, reset, false},
+		{"PATCH", status(503), nil, false},
+		{"DELETE", status(429), nil, true},
+		{"DELETE", status(503), nil, false},
+		{"DELETE", nil, reset, false},
+		{"GET", nil, fmt.Errorf("request failed: %w", ErrNotRecorded), false},
+		{"GET", nil, fmt.Errorf("request failed: %w", ErrDryRun), false},
+	}
+	for _, tt := range tests {
+		reason := fmt.Sprint(tt.err)
+		if tt.resp != nil {
+			reason = fmt.Sprintf("HTTP %d", tt.resp.StatusCode)
+		}
+		if got := shouldRetry(context.Background(), tt.resp, tt.err, isIdempotentMethod(tt.method)); got != tt.want {
+			t.Errorf("shouldRetry(%s after %s) = %t, want %t", tt.method, reason, got, tt.want)
+		}
+	}
+
+	// A request marked Idempotent is retried like a GET.
+	if !shouldRetry(context.Background(), status(503), nil, true) {
+		t.Error("shouldRetry(idempotent POST after HTTP 503) = false, want true")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if shouldRetry(ctx, status(503), nil, true) {
+		t.Error("shouldRetry after the context was cancelled = true, want false")
+	}
+}
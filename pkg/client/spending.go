@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+)
+
+// SpendingSummary aggregates Medicaid provider spending for one HCPCS code.
+type SpendingSummary struct {
+	TotalPaid           float64        `json:"total_paid"`
+	TotalClaims         int64          `json:"total_claims"`
+	UniqueBeneficiaries int64          `json:"unique_beneficiaries"`
+	UniqueProviders     int64          `json:"unique_providers"`
+	ByYear              []YearSpending `json:"by_year,omitempty"`
+}
+
+// YearSpending is the spending for a code in a single year.
+type YearSpending struct {
+	Year        int     `json:"year"`
+	TotalPaid   float64 `json:"total_paid"`
+	TotalClaims int64   `json:"total_claims"`
+}
+
+// SpendingOptions narrows a spending lookup.
+type SpendingOptions struct {
+	// Year limits the summaries to one year. Zero returns all available
+	// years.
+	Year int
+}
+
+// SpendingByCode returns spending summaries keyed by HCPCS code. opts may
+// be nil.
+func (c *Client) SpendingByCode(ctx context.Context, codes []string, opts *SpendingOptions) (*Response[map[string]SpendingSummary], error) {
+	if opts == nil {
+		opts = &SpendingOptions{}
+	}
+
+	q := NewQuery()
+	if len(codes) == 1 {
+		q.Set("code", codes[0])
+	} else {
+		q.Join("codes", codes)
+	}
+	q.SetInt("year", opts.Year)
+	path := withQuery(endpoint("spending", "by-code"), q)
+
+	var result Response[map[string]SpendingSummary]
+	if err := c.Get(ctx, path, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
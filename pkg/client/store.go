@@ -0,0 +1,154 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cacheEntry is a stored response, as kept by Cache and Validators.
+type cacheEntry struct {
+	Method       string          `json:"method"`
+	URL          string          `json:"url"`
+	StoredAt     time.Time       `json:"stored_at"`
+	Expires      time.Time       `json:"expires"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"last_modified,omitempty"`
+	Body         json.RawMessage `json:"body"`
+}
+
+const entryExt = ".json"
+
+// diskStore keeps one JSON file per request in a directory.
+type diskStore struct {
+	dir string
+}
+
+// file returns the entry path for a request made with credential. The
+// credential is part of the key, so accounts never see each other's
+// responses, but only its hash reaches the disk.
+func (s diskStore) file(credential, method, url string, payload []byte) string {
+	cred := sha256.Sum256([]byte(credential))
+	body := sha256.Sum256(payload)
+	key := sha256.Sum256([]byte(hex.EncodeToString(cred[:]) + " " + method + " " + url + " " + hex.EncodeToString(body[:])))
+	return filepath.Join(s.dir, hex.EncodeToString(key[:])+entryExt)
+}
+
+func (s diskStore) read(path string) (*cacheEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// write stores entry at path through a temporary file, so a concurrent
+// reader never sees a partial entry.
+func (s diskStore) write(path string, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// stats counts the entries in the store.
+func (s diskStore) stats() (CacheStats, error) {
+	stats := CacheStats{Dir: s.dir}
+	now := time.Now()
+	err := s.walk(func(path string, info fs.FileInfo) error {
+		entry, err := s.read(path)
+		if err != nil {
+			return nil
+		}
+		stats.Entries++
+		stats.Bytes += info.Size()
+		if !entry.Expires.IsZero() && now.After(entry.Expires) {
+			stats.Expired++
+		}
+		if stats.Oldest == nil || entry.StoredAt.Before(*stats.Oldest) {
+			stats.Oldest = &entry.StoredAt
+		}
+		if stats.Newest == nil || entry.StoredAt.After(*stats.Newest) {
+			stats.Newest = &entry.StoredAt
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// remove deletes the entries for which match returns true and returns how
+// many it deleted. Unreadable entries are passed to match as nil.
+func (s diskStore) remove(match func(entry *cacheEntry) bool) (int, error) {
+	removed := 0
+	err := s.walk(func(path string, _ fs.FileInfo) error {
+		entry, err := s.read(path)
+		if err != nil {
+			entry = nil
+		}
+		if !match(entry) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+// walk calls fn for every entry file. A missing directory is an empty
+// store.
+func (s diskStore) walk(fn func(path string, info fs.FileInfo) error) error {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != entryExt {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if err := fn(filepath.Join(s.dir, e.Name()), info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redactedHeaders are never written out in full by a Tracer.
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// Tracer is an http.RoundTripper that writes every exchange to Out in a
+// curl -v style: the method, full URL, headers and body of the request,
+// then the status, time taken and headers of the response. Credentials are
+// redacted. Response bodies are included only when Bodies is set, since
+// they can be large.
+type Tracer struct {
+	// Transport sends the requests. Nil means http.DefaultTransport.
+	Transport http.RoundTripper
+	Out       io.Writer
+	Bodies    bool
+
+	mu sync.Mutex
+}
+
+// NewTracer returns a tracer logging requests sent through transport to out.
+func NewTracer(transport http.RoundTripper, out io.Writer, bodies bool) *Tracer {
+	return &Tracer{Transport: transport, Out: out, Bodies: bodies}
+}
+
+// RoundTrip sends req and logs the exchange.
+func (t *Tracer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "> %s %s\n", req.Method, req.URL)
+	writeHeaders(&buf, "> ", req.Header)
+	if body, err := requestBody(req); err != nil {
+		fmt.Fprintf(&buf, "> (body unreadable: %v)\n", err)
+	} else if len(body) > 0 {
+		writeBody(&buf, "> ", body)
+	}
+	t.write(buf.Bytes())
+	buf.Reset()
+
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	start := time.Now()
+	resp, err := transport.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(&buf, "< error after %s: %v\n\n", elapsed, err)
+		t.write(buf.Bytes())
+		return nil, err
+	}
+
+	fmt.Fprintf(&buf, "< %s %s (%s)\n", resp.Proto, resp.Status, elapsed)
+	writeHeaders(&buf, "< ", resp.Header)
+	if t.Bodies && resp.Body != nil {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			fmt.Fprintf(&buf, "< (body unreadable: %v)\n", err)
+		} else if len(body) > 0 {
+			writeBody(&buf, "< ", body)
+		}
+	}
+	buf.WriteString("\n")
+	t.write(buf.Bytes())
+	return resp, nil
+}
+
+// write outputs one block at a time, so concurrent requests do not
+// interleave.
+func (t *Tracer) write(p []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Out.Write(p)
+}
+
+// requestBody returns a copy of req's body, leaving it readable for the
+// transport.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		r, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, err
+}
+
+func writeHeaders(w io.Writer, prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(w, "%s%s: %s\n", prefix, name, redactHeader(name, value))
+		}
+	}
+}
+
+// redactHeader hides a credential, keeping the scheme of an Authorization
+// header so it is clear which kind was sent.
+func redactHeader(name, value string) string {
+	if !redactedHeaders[http.CanonicalHeaderKey(name)] {
+		return value
+	}
+	if scheme, _, ok := strings.Cut(value, " "); ok && http.CanonicalHeaderKey(name) == "Authorization" {
+		return scheme + " [REDACTED]"
+	}
+	return "[REDACTED]"
+}
+
+func writeBody(w io.Writer, prefix string, body []byte) {
+	fmt.Fprintf(w, "%s\n", strings.TrimSpace(prefix))
+	for _, line := range strings.Split(strings.TrimRight(string(body), "\n"), "\n") {
+		fmt.Fprintf(w, "%s%s\n", prefix, line)
+	}
+}
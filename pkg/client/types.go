@@ -0,0 +1,112 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Response is the envelope every Verity endpoint wraps its payload in.
+//
+// Raw keeps the body exactly as the server sent it, and a Response
+// marshals back to Raw when it has one, so JSON output carries every field
+// the API returns, including ones the models here do not cover yet.
+type Response[T any] struct {
+	Success bool            `json:"success"`
+	Data    T               `json:"data"`
+	Meta    json.RawMessage `json:"meta,omitempty"`
+	Raw     json.RawMessage `json:"-"`
+
+	mismatch error
+}
+
+// responseFields is Response without its JSON methods.
+type responseFields[T any] Response[T]
+
+// UnmarshalJSON decodes the envelope and keeps a copy of b in Raw. A field
+// whose type differs from the model is left at its zero value rather than
+// failing the whole response; Mismatch reports it.
+func (r *Response[T]) UnmarshalJSON(b []byte) error {
+	var fields responseFields[T]
+	err := json.Unmarshal(b, &fields)
+	var typeErr *json.UnmarshalTypeError
+	if err != nil && !errors.As(err, &typeErr) {
+		return err
+	}
+	*r = Response[T](fields)
+	r.Raw = append(json.RawMessage(nil), b...)
+	if typeErr != nil {
+		r.mismatch = fmt.Errorf("field %s is a %s, expected %s", typeErr.Field, typeErr.Value, typeErr.Type)
+	}
+	return nil
+}
+
+// MarshalJSON returns Raw when the response was decoded from the API, and
+// the fields otherwise.
+func (r Response[T]) MarshalJSON() ([]byte, error) {
+	if len(r.Raw) > 0 {
+		return r.Raw, nil
+	}
+	return json.Marshal(responseFields[T](r))
+}
+
+// Mismatch returns the first field in the response whose JSON type did not
+// match the model, or nil.
+func (r *Response[T]) Mismatch() error {
+	return r.mismatch
+}
+
+// RawRecords returns the elements of the data array as the server sent
+// them. It reports false when there is no Raw or data is not an array.
+func (r *Response[T]) RawRecords() ([]json.RawMessage, bool) {
+	if len(r.Raw) == 0 {
+		return nil, false
+	}
+	var envelope struct {
+		Data []json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(r.Raw, &envelope); err != nil || envelope.Data == nil {
+		return nil, false
+	}
+	return envelope.Data, true
+}
+
+// Decimal is a numeric value the API may send either as a JSON string
+// ("1.23") or as a bare number. It keeps the textual form so no precision
+// is lost on the way through the CLI.
+type Decimal string
+
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*d = ""
+		return nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*d = Decimal(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	*d = Decimal(n)
+	return nil
+}
+
+// Float64 parses the decimal, reporting false when it is empty or malformed.
+func (d Decimal) Float64() (float64, bool) {
+	if d == "" {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(string(d), 64)
+	return f, err == nil
+}
+
+func (d Decimal) String() string {
+	return string(d)
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+)
+
+// Webhook is a webhook subscription. Secret is only returned on creation.
+type Webhook struct {
+	ID        string   `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Status    string   `json:"status,omitempty"`
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt string   `json:"created_at,omitempty"`
+}
+
+// WebhookRequest creates or updates a webhook. Empty fields are left
+// unchanged on update.
+type WebhookRequest struct {
+	URL    string   `json:"url,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+// WebhookTestResult is the outcome of delivering a test event.
+type WebhookTestResult struct {
+	Status     string  `json:"status"`
+	StatusCode int     `json:"status_code,omitempty"`
+	DurationMs float64 `json:"duration_ms,omitempty"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// DeleteResult acknowledges a deletion.
+type DeleteResult struct {
+	ID      string `json:"id,omitempty"`
+	Deleted bool   `json:"deleted"`
+}
+
+// ListWebhooks lists all webhooks.
+func (c *Client) ListWebhooks(ctx context.Context) (*Response[[]Webhook], error) {
+	var result Response[[]Webhook]
+	if err := c.Get(ctx, "/webhooks", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CreateWebhook creates a webhook subscription.
+func (c *Client) CreateWebhook(ctx context.Context, req WebhookRequest) (*Response[Webhook], error) {
+	var result Response[Webhook]
+	if err := c.Post(ctx, "/webhooks", req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateWebhook changes a webhook's URL or events.
+func (c *Client) UpdateWebhook(ctx context.Context, webhookID string, req WebhookRequest) (*Response[Webhook], error) {
+	path := endpoint("webhooks", webhookID)
+
+	var result Response[Webhook]
+	if err := c.Request(ctx, "PATCH", path, req, &result, Idempotent()); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteWebhook deletes a webhook.
+func (c *Client) DeleteWebhook(ctx context.Context, webhookID string) (*Response[DeleteResult], error) {
+	path := endpoint("webhooks", webhookID)
+
+	var result Response[DeleteResult]
+	if err := c.Request(ctx, "DELETE", path, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// TestWebhook sends a test event to a webhook.
+func (c *Client) TestWebhook(ctx context.Context, webhookID string) (*Response[WebhookTestResult], error) {
+	path := endpoint("webhooks", webhookID, "test")
+
+	var result Response[WebhookTestResult]
+	if err := c.Post(ctx, path, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
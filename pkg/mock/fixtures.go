@@ -0,0 +1,101 @@
+package mock
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/tylerbryy/verity-cli/pkg/client"
+)
+
+//go:embed fixtures/*.json
+var builtin embed.FS
+
+// Fixtures is the data the mock server answers from, one file per field.
+type Fixtures struct {
+	Codes         map[string]client.CodeLookup
+	Policies      []client.Policy
+	PolicyChanges []client.PolicyChange
+	// PriorAuth and Evaluate are keyed by procedure code and policy ID.
+	// The "*" entry answers everything else.
+	PriorAuth     map[string]client.PriorAuthResult
+	Research      client.ResearchResult
+	Evaluate      map[string]client.EvaluateResult
+	Spending      map[string]client.SpendingSummary
+	Jurisdictions []client.Jurisdiction
+	Webhooks      []client.Webhook
+}
+
+// files maps each fixture file to the field it is decoded into.
+func (f *Fixtures) files() map[string]interface{} {
+	return map[string]interface{}{
+		"codes.json":          &f.Codes,
+		"policies.json":       &f.Policies,
+		"policy-changes.json": &f.PolicyChanges,
+		"prior-auth.json":     &f.PriorAuth,
+		"research.json":       &f.Research,
+		"evaluate.json":       &f.Evaluate,
+		"spending.json":       &f.Spending,
+		"jurisdictions.json":  &f.Jurisdictions,
+		"webhooks.json":       &f.Webhooks,
+	}
+}
+
+// LoadFixtures returns the built-in fixtures, with any file of the same
+// name in dir used instead. An empty dir uses the built-in set only.
+func LoadFixtures(dir string) (*Fixtures, error) {
+	f := &Fixtures{}
+	for name, dest := range f.files() {
+		data, err := builtin.ReadFile(path.Join("fixtures", name))
+		if err != nil {
+			return nil, err
+		}
+		if dir != "" {
+			override, err := os.ReadFile(filepath.Join(dir, name))
+			switch {
+			case err == nil:
+				data = override
+			case !errors.Is(err, fs.ErrNotExist):
+				return nil, err
+			}
+		}
+		if err := json.Unmarshal(data, dest); err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", name, err)
+		}
+	}
+	return f, nil
+}
+
+// WriteFixtures copies the built-in fixtures into dir, as a starting point
+// for overrides. Existing files are left alone.
+func WriteFixtures(dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	entries, err := builtin.ReadDir("fixtures")
+	if err != nil {
+		return nil, err
+	}
+
+	var written []string
+	for _, e := range entries {
+		dest := filepath.Join(dir, e.Name())
+		if _, err := os.Stat(dest); err == nil {
+			continue
+		}
+		data, err := builtin.ReadFile(path.Join("fixtures", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(dest, data, 0o644); err != nil {
+			return nil, err
+		}
+		written = append(written, dest)
+	}
+	return written, nil
+}
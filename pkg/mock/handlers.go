@@ -0,0 +1,568 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tylerbryy/verity-cli/pkg/client"
+)
+
+// Version is reported by the mock's health endpoint.
+const Version = "mock"
+
+func (s *Server) notFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotFound, fmt.Sprintf("No endpoint %s %s", r.Method, r.URL.Path), "")
+}
+
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	writeData(w, r, client.HealthStatus{
+		Status:    "healthy",
+		Version:   Version,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Checks: map[string]client.HealthCheck{
+			"database": {Status: "healthy", LatencyMs: 1.2},
+			"redis":    {Status: "healthy", LatencyMs: 0.4},
+		},
+	}, nil)
+}
+
+func (s *Server) lookupCode(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	code := q.Get("code")
+	if code == "" {
+		writeError(w, http.StatusBadRequest, "code is required", "Pass ?code=76942")
+		return
+	}
+	writeData(w, r, s.code(code, splitList(q.Get("include"))), nil)
+}
+
+func (s *Server) batchLookup(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Codes   []string `json:"codes"`
+		Include string   `json:"include"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if len(req.Codes) == 0 {
+		writeError(w, http.StatusBadRequest, "codes is required", "")
+		return
+	}
+
+	results := make([]client.CodeLookup, 0, len(req.Codes))
+	for _, code := range req.Codes {
+		results = append(results, s.code(code, splitList(req.Include)))
+	}
+	writeData(w, r, results, nil)
+}
+
+// code returns the lookup for code, with RVU and policies only when
+// include asks for them, as the API does.
+func (s *Server) code(code string, include []string) client.CodeLookup {
+	found, ok := s.fixtures.Codes[strings.ToUpper(code)]
+	if !ok {
+		return client.CodeLookup{Code: code, Found: false}
+	}
+	if !contains(include, "rvu") {
+		found.RVU = nil
+	}
+	if !contains(include, "policies") {
+		found.Policies = nil
+	}
+	return found
+}
+
+func (s *Server) listPolicies(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	search := strings.ToLower(q.Get("q"))
+	status := q.Get("status")
+
+	var matches []client.Policy
+	for _, p := range s.fixtures.Policies {
+		switch {
+		case search != "" && !strings.Contains(strings.ToLower(p.Title+" "+p.Description), search):
+		case !matchFold(q.Get("policy_type"), p.PolicyType):
+		case !matchFold(q.Get("jurisdiction"), p.Jurisdiction):
+		case status != "" && status != "all" && !strings.EqualFold(status, p.Status):
+		case q.Get("icd10") != "" && !hasCode(p, q.Get("icd10")):
+		default:
+			matches = append(matches, summary(p))
+		}
+	}
+	page, meta := paginate(r, matches)
+	writeData(w, r, page, meta)
+}
+
+func (s *Server) getPolicy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for _, p := range s.fixtures.Policies {
+		if !strings.EqualFold(p.PolicyID, id) {
+			continue
+		}
+		include := splitList(r.URL.Query().Get("include"))
+		if !contains(include, "criteria") {
+			p.Criteria = nil
+		}
+		if !contains(include, "codes") {
+			p.Codes = nil
+		}
+		if !contains(include, "attachments") {
+			p.Attachments = nil
+		}
+		if !contains(include, "versions") {
+			p.Versions = nil
+		}
+		writeData(w, r, p, nil)
+		return
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("Policy %s not found", id), "Search with 'verity policies list'")
+}
+
+func (s *Server) policyChanges(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var matches []client.PolicyChange
+	for _, c := range s.fixtures.PolicyChanges {
+		switch {
+		case q.Get("since") != "" && c.Timestamp < q.Get("since"):
+		case !matchFold(q.Get("policy_id"), c.PolicyID):
+		case !matchFold(q.Get("change_type"), c.ChangeType):
+		default:
+			matches = append(matches, c)
+		}
+	}
+	page, meta := paginate(r, matches)
+	writeData(w, r, page, meta)
+}
+
+func (s *Server) comparePolicies(w http.ResponseWriter, r *http.Request) {
+	var req client.ComparePoliciesRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if len(req.ProcedureCodes) == 0 {
+		writeError(w, http.StatusBadRequest, "procedure_codes is required", "")
+		return
+	}
+
+	var result client.PolicyComparison
+	for _, j := range s.fixtures.Jurisdictions {
+		if len(req.Jurisdictions) > 0 && !containsFold(req.Jurisdictions, j.JurisdictionCode) {
+			continue
+		}
+		comp := client.JurisdictionPolicies{Jurisdiction: j.JurisdictionCode, MacName: j.MacName, Policies: []client.Policy{}}
+		for _, p := range s.fixtures.Policies {
+			if p.Jurisdiction != j.JurisdictionCode || !matchFold(req.PolicyType, p.PolicyType) {
+				continue
+			}
+			for _, code := range req.ProcedureCodes {
+				if hasCode(p, code) {
+					comp.Policies = append(comp.Policies, summary(p))
+					break
+				}
+			}
+		}
+		result.Comparison = append(result.Comparison, comp)
+	}
+	writeData(w, r, result, nil)
+}
+
+func (s *Server) checkPriorAuth(w http.ResponseWriter, r *http.Request) {
+	var req client.PriorAuthRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if len(req.ProcedureCodes) == 0 {
+		writeError(w, http.StatusBadRequest, "procedure_codes is required", "")
+		return
+	}
+
+	// Merge the answers for every code: prior auth is required if any code
+	// needs it, and the first such code gives the reason.
+	var result *client.PriorAuthResult
+	seen := map[string]bool{}
+	for _, code := range req.ProcedureCodes {
+		answer, ok := s.fixtures.PriorAuth[strings.ToUpper(code)]
+		if !ok {
+			continue
+		}
+		if result == nil {
+			result = &client.PriorAuthResult{Confidence: answer.Confidence, Reason: answer.Reason}
+		}
+		if answer.PARequired && !result.PARequired {
+			result.PARequired, result.Confidence, result.Reason = true, answer.Confidence, answer.Reason
+		}
+		result.MatchedPolicies = append(result.MatchedPolicies, answer.MatchedPolicies...)
+		for _, item := range answer.DocumentationChecklist {
+			if !seen[item] {
+				seen[item] = true
+				result.DocumentationChecklist = append(result.DocumentationChecklist, item)
+			}
+		}
+	}
+	if result == nil {
+		fallback := s.fixtures.PriorAuth["*"]
+		result = &fallback
+	}
+	writeData(w, r, result, nil)
+}
+
+// researchTask is a research request that completes after being polled
+// once, so clients see both states.
+type researchTask struct {
+	task  client.ResearchTask
+	polls int
+}
+
+func (s *Server) startResearch(w http.ResponseWriter, r *http.Request) {
+	var req client.ResearchRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if len(req.ProcedureCodes) == 0 {
+		writeError(w, http.StatusBadRequest, "procedure_codes is required", "")
+		return
+	}
+
+	s.mu.Lock()
+	s.nextTask++
+	id := fmt.Sprintf("res_mock_%d", s.nextTask)
+	t := &researchTask{task: client.ResearchTask{
+		ResearchID: id,
+		Status:     "pending",
+		CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+		PollURL:    "/prior-auth/research/" + id,
+	}}
+	if req.Sync {
+		s.complete(t)
+	}
+	s.research[id] = t
+	task := t.task
+	s.mu.Unlock()
+
+	writeData(w, r, task, nil)
+}
+
+func (s *Server) getResearch(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	t, ok := s.research[r.PathValue("id")]
+	if ok {
+		if t.polls++; t.polls > 1 {
+			s.complete(t)
+		} else {
+			t.task.Status = "processing"
+		}
+	}
+	var task client.ResearchTask
+	if ok {
+		task = t.task
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "Research task not found", "Start one with 'verity prior-auth research'")
+		return
+	}
+	writeData(w, r, task, nil)
+}
+
+// complete finishes t with the research fixture. s.mu must be held.
+func (s *Server) complete(t *researchTask) {
+	result := s.fixtures.Research
+	t.task.Status = "completed"
+	t.task.Result = &result
+}
+
+func (s *Server) searchCriteria(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	search := strings.ToLower(q.Get("q"))
+	if search == "" {
+		writeError(w, http.StatusBadRequest, "q is required", "")
+		return
+	}
+
+	var matches []client.CriteriaBlock
+	for _, p := range s.fixtures.Policies {
+		if !matchFold(q.Get("policy_type"), p.PolicyType) || !matchFold(q.Get("jurisdiction"), p.Jurisdiction) {
+			continue
+		}
+		for _, c := range p.Criteria {
+			if !matchFold(q.Get("section"), c.Section) {
+				continue
+			}
+			if !strings.Contains(strings.ToLower(c.Text+" "+p.Title), search) {
+				continue
+			}
+			matches = append(matches, client.CriteriaBlock{
+				PolicyID:    p.PolicyID,
+				PolicyTitle: p.Title,
+				Section:     c.Section,
+				Text:        c.Text,
+			})
+		}
+	}
+	page, meta := paginate(r, matches)
+	writeData(w, r, page, meta)
+}
+
+func (s *Server) evaluateCoverage(w http.ResponseWriter, r *http.Request) {
+	var req client.EvaluateRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if !s.hasPolicy(req.PolicyID) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Policy %s not found", req.PolicyID), "")
+		return
+	}
+
+	result, ok := s.fixtures.Evaluate[strings.ToUpper(req.PolicyID)]
+	if !ok {
+		result = s.fixtures.Evaluate["*"]
+		result.PolicyID = req.PolicyID
+	}
+	writeData(w, r, result, nil)
+}
+
+func (s *Server) spendingByCode(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	codes := splitList(q.Get("codes"))
+	if code := q.Get("code"); code != "" {
+		codes = append(codes, code)
+	}
+	if len(codes) == 0 {
+		writeError(w, http.StatusBadRequest, "code or codes is required", "")
+		return
+	}
+	year, _ := strconv.Atoi(q.Get("year"))
+
+	result := map[string]client.SpendingSummary{}
+	for _, code := range codes {
+		summary, ok := s.fixtures.Spending[strings.ToUpper(code)]
+		if !ok {
+			continue
+		}
+		if year > 0 {
+			summary = spendingForYear(summary, year)
+		}
+		result[code] = summary
+	}
+	writeData(w, r, result, nil)
+}
+
+// spendingForYear narrows a summary to one year's figures. Beneficiary and
+// provider counts are not broken down by year, so they are kept.
+func spendingForYear(summary client.SpendingSummary, year int) client.SpendingSummary {
+	narrowed := client.SpendingSummary{
+		UniqueBeneficiaries: summary.UniqueBeneficiaries,
+		UniqueProviders:     summary.UniqueProviders,
+	}
+	for _, y := range summary.ByYear {
+		if y.Year == year {
+			narrowed.TotalPaid, narrowed.TotalClaims = y.TotalPaid, y.TotalClaims
+			narrowed.ByYear = []client.YearSpending{y}
+		}
+	}
+	return narrowed
+}
+
+func (s *Server) listJurisdictions(w http.ResponseWriter, r *http.Request) {
+	writeData(w, r, s.fixtures.Jurisdictions, nil)
+}
+
+func (s *Server) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	hooks := append([]client.Webhook{}, s.webhooks...)
+	s.mu.Unlock()
+	writeData(w, r, hooks, nil)
+}
+
+func (s *Server) createWebhook(w http.ResponseWriter, r *http.Request) {
+	var req client.WebhookRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.URL == "" || len(req.Events) == 0 {
+		writeError(w, http.StatusBadRequest, "url and events are required", "")
+		return
+	}
+
+	s.mu.Lock()
+	hook := client.Webhook{
+		ID:        fmt.Sprintf("wh_%d", s.nextWebhook),
+		URL:       req.URL,
+		Events:    req.Events,
+		Status:    "active",
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	s.nextWebhook++
+	s.webhooks = append(s.webhooks, hook)
+	s.mu.Unlock()
+
+	// The secret is only ever shown on creation.
+	hook.Secret = "whsec_mock_" + hook.ID
+	writeData(w, r, hook, nil)
+}
+
+func (s *Server) updateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req client.WebhookRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	i := s.webhookIndex(r.PathValue("id"))
+	var hook client.Webhook
+	if i >= 0 {
+		if req.URL != "" {
+			s.webhooks[i].URL = req.URL
+		}
+		if len(req.Events) > 0 {
+			s.webhooks[i].Events = req.Events
+		}
+		hook = s.webhooks[i]
+	}
+	s.mu.Unlock()
+
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Webhook not found", "List webhooks with 'verity webhooks list'")
+		return
+	}
+	writeData(w, r, hook, nil)
+}
+
+func (s *Server) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	i := s.webhookIndex(id)
+	if i >= 0 {
+		s.webhooks = append(s.webhooks[:i], s.webhooks[i+1:]...)
+	}
+	s.mu.Unlock()
+
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Webhook not found", "List webhooks with 'verity webhooks list'")
+		return
+	}
+	writeData(w, r, client.DeleteResult{ID: id, Deleted: true}, nil)
+}
+
+func (s *Server) testWebhook(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	found := s.webhookIndex(r.PathValue("id")) >= 0
+	s.mu.Unlock()
+
+	if !found {
+		writeError(w, http.StatusNotFound, "Webhook not found", "List webhooks with 'verity webhooks list'")
+		return
+	}
+	writeData(w, r, client.WebhookTestResult{Status: "delivered", StatusCode: http.StatusOK, DurationMs: 42}, nil)
+}
+
+// webhookIndex returns the position of the webhook with id, or -1. s.mu
+// must be held.
+func (s *Server) webhookIndex(id string) int {
+	for i, h := range s.webhooks {
+		if h.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) hasPolicy(id string) bool {
+	for _, p := range s.fixtures.Policies {
+		if strings.EqualFold(p.PolicyID, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// paginate returns the page of items selected by the limit and page query
+// parameters, with the API's pagination meta.
+func paginate[T any](r *http.Request, items []T) ([]T, map[string]interface{}) {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page <= 0 {
+		page = 1
+	}
+
+	total := len(items)
+	start := min((page-1)*limit, total)
+	end := min(start+limit, total)
+	pageItems := append([]T{}, items[start:end]...)
+	return pageItems, map[string]interface{}{
+		"page":        page,
+		"per_page":    limit,
+		"total":       total,
+		"total_pages": (total + limit - 1) / limit,
+		"has_more":    end < total,
+	}
+}
+
+// summary strips the detail sections from a policy, as list endpoints do.
+func summary(p client.Policy) client.Policy {
+	p.Description, p.Summary = "", ""
+	p.Criteria, p.Codes, p.Attachments, p.Versions = nil, nil, nil, nil
+	return p
+}
+
+func hasCode(p client.Policy, code string) bool {
+	for _, c := range p.Codes {
+		if strings.EqualFold(c.Code, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeBody reads a JSON request body into v, answering 400 when it is
+// malformed.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON body: "+err.Error(), "")
+		return false
+	}
+	return true
+}
+
+// splitList splits a comma-separated parameter, dropping empty items.
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// matchFold reports whether value satisfies an optional filter.
+func matchFold(filter, value string) bool {
+	return filter == "" || strings.EqualFold(filter, value)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
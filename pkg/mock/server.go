@@ -0,0 +1,253 @@
+// Package mock implements a local stand-in for the Verity API, serving
+// every endpoint the CLI calls from fixtures, for offline use and tests.
+package mock
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tylerbryy/verity-cli/pkg/client"
+)
+
+// BasePath is the path prefix of the real API. Requests are accepted with
+// or without it.
+const BasePath = "/api/v1"
+
+// Options controls the server's simulated behaviour.
+type Options struct {
+	// APIKey, when set, is the only bearer token accepted. Otherwise any
+	// non-empty token is.
+	APIKey string
+	// Latency delays every response.
+	Latency time.Duration
+	// ErrorRate is the fraction of requests, from 0 to 1, that fail with
+	// 503 Service Unavailable.
+	ErrorRate float64
+	// RateLimit is the number of requests allowed per minute before
+	// answering 429 with Retry-After. Zero means unlimited.
+	RateLimit int
+	// Failures maps path prefixes, such as "/policies", to a status code
+	// every matching request fails with.
+	Failures map[string]int
+	// Logf, when set, receives a line per request.
+	Logf func(format string, args ...interface{})
+}
+
+// Server is an http.Handler implementing the Verity API.
+type Server struct {
+	opts     Options
+	fixtures *Fixtures
+	mux      *http.ServeMux
+
+	mu          sync.Mutex
+	windowStart time.Time
+	windowCount int
+	requests    int
+	webhooks    []client.Webhook
+	nextWebhook int
+	research    map[string]*researchTask
+	nextTask    int
+}
+
+// NewServer returns a server answering from fixtures.
+func NewServer(fixtures *Fixtures, opts Options) *Server {
+	s := &Server{
+		opts:     opts,
+		fixtures: fixtures,
+		mux:      http.NewServeMux(),
+		webhooks: append([]client.Webhook(nil), fixtures.Webhooks...),
+		research: map[string]*researchTask{},
+	}
+	s.nextWebhook = len(s.webhooks) + 1
+	s.routes()
+	return s
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /health", s.health)
+	s.mux.HandleFunc("GET /codes/lookup", s.lookupCode)
+	s.mux.HandleFunc("POST /codes/batch", s.batchLookup)
+	s.mux.HandleFunc("GET /policies", s.listPolicies)
+	s.mux.HandleFunc("GET /policies/changes", s.policyChanges)
+	s.mux.HandleFunc("POST /policies/compare", s.comparePolicies)
+	s.mux.HandleFunc("GET /policies/{id}", s.getPolicy)
+	s.mux.HandleFunc("POST /prior-auth/check", s.checkPriorAuth)
+	s.mux.HandleFunc("POST /prior-auth/research", s.startResearch)
+	s.mux.HandleFunc("GET /prior-auth/research/{id}", s.getResearch)
+	s.mux.HandleFunc("GET /coverage/criteria", s.searchCriteria)
+	s.mux.HandleFunc("POST /coverage/evaluate", s.evaluateCoverage)
+	s.mux.HandleFunc("GET /spending/by-code", s.spendingByCode)
+	s.mux.HandleFunc("GET /jurisdictions", s.listJurisdictions)
+	s.mux.HandleFunc("GET /webhooks", s.listWebhooks)
+	s.mux.HandleFunc("POST /webhooks", s.createWebhook)
+	s.mux.HandleFunc("PATCH /webhooks/{id}", s.updateWebhook)
+	s.mux.HandleFunc("DELETE /webhooks/{id}", s.deleteWebhook)
+	s.mux.HandleFunc("POST /webhooks/{id}/test", s.testWebhook)
+	s.mux.HandleFunc("/", s.notFound)
+}
+
+// ServeHTTP applies the simulated conditions, then routes the request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	defer func() {
+		if s.opts.Logf != nil {
+			s.opts.Logf("%s %s %d %s", r.Method, r.URL.RequestURI(), rec.status, time.Since(start).Round(time.Microsecond))
+		}
+	}()
+
+	if p, ok := strings.CutPrefix(r.URL.Path, BasePath); ok {
+		r.URL.Path = p
+		r.URL.RawPath = strings.TrimPrefix(r.URL.RawPath, BasePath)
+	}
+	rec.Header().Set("X-Request-Id", s.requestID())
+
+	if s.opts.Latency > 0 {
+		select {
+		case <-time.After(s.opts.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if !s.authorized(r) {
+		writeError(rec, http.StatusUnauthorized, "Invalid or missing API key", "Send the key as 'Authorization: Bearer <key>'")
+		return
+	}
+	if wait, ok := s.allow(); !ok {
+		rec.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds()+0.999)))
+		writeError(rec, http.StatusTooManyRequests, "Rate limit exceeded", fmt.Sprintf("The mock server allows %d requests per minute", s.opts.RateLimit))
+		return
+	}
+	for prefix, status := range s.opts.Failures {
+		if r.URL.Path == prefix || strings.HasPrefix(r.URL.Path, strings.TrimSuffix(prefix, "/")+"/") {
+			if status == http.StatusTooManyRequests {
+				rec.Header().Set("Retry-After", "1")
+			}
+			writeError(rec, status, "Simulated failure for "+prefix, "")
+			return
+		}
+	}
+	if s.opts.ErrorRate > 0 && rand.Float64() < s.opts.ErrorRate {
+		writeError(rec, http.StatusServiceUnavailable, "Simulated outage", "")
+		return
+	}
+
+	s.mux.ServeHTTP(rec, r)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return false
+	}
+	return s.opts.APIKey == "" || token == s.opts.APIKey
+}
+
+// allow counts a request against the per-minute rate limit and, when it is
+// over, reports how long until the window resets.
+func (s *Server) allow() (time.Duration, bool) {
+	if s.opts.RateLimit <= 0 {
+		return 0, true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.windowStart) >= time.Minute {
+		s.windowStart, s.windowCount = now, 0
+	}
+	s.windowCount++
+	if s.windowCount > s.opts.RateLimit {
+		return s.windowStart.Add(time.Minute).Sub(now), false
+	}
+	return 0, true
+}
+
+func (s *Server) requestID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	return fmt.Sprintf("req_mock_%06d", s.requests)
+}
+
+// writeData writes a success envelope. GET responses carry an ETag, and a
+// matching If-None-Match is answered with 304 Not Modified.
+func writeData(w http.ResponseWriter, r *http.Request, data interface{}, meta interface{}) {
+	body := map[string]interface{}{"success": true, "data": data}
+	if meta != nil {
+		body["meta"] = meta
+	}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error(), "")
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		sum := sha256.Sum256(encoded)
+		etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+}
+
+// writeError writes the API's error envelope.
+func writeError(w http.ResponseWriter, status int, message, hint string) {
+	apiErr := map[string]interface{}{
+		"code":       errorCode(status),
+		"message":    message,
+		"request_id": w.Header().Get("X-Request-Id"),
+	}
+	if hint != "" {
+		apiErr["hint"] = hint
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "error": apiErr})
+}
+
+func errorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "VALIDATION_ERROR"
+	case http.StatusUnauthorized:
+		return "UNAUTHORIZED"
+	case http.StatusForbidden:
+		return "FORBIDDEN"
+	case http.StatusNotFound:
+		return "NOT_FOUND"
+	case http.StatusTooManyRequests:
+		return "RATE_LIMITED"
+	case http.StatusServiceUnavailable:
+		return "SERVICE_UNAVAILABLE"
+	}
+	if status >= 500 {
+		return "INTERNAL_ERROR"
+	}
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
+
+// statusRecorder remembers the status written, for the request log.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
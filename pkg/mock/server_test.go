@@ -0,0 +1,473 @@
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// envelope is the part of a response body the tests look at.
+type envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Meta    struct {
+		Page       int  `json:"page"`
+		Total      int  `json:"total"`
+		TotalPages int  `json:"total_pages"`
+		HasMore    bool `json:"has_more"`
+	} `json:"meta"`
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func newServer(t *testing.T, opts Options) *Server {
+	t.Helper()
+	fixtures, err := LoadFixtures("")
+	if err != nil {
+		t.Fatalf("LoadFixtures: %v", err)
+	}
+	return NewServer(fixtures, opts)
+}
+
+// serve sends one request to s with the API key "test".
+func serve(t *testing.T, s *Server, method, target, body string) (*httptest.ResponseRecorder, envelope) {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test")
+	return record(t, s, req)
+}
+
+func record(t *testing.T, s *Server, req *http.Request) (*httptest.ResponseRecorder, envelope) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	var env envelope
+	if rec.Code != http.StatusNotModified {
+		if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+			t.Fatalf("%s %s: decoding %q: %v", req.Method, req.URL, rec.Body.String(), err)
+		}
+	}
+	return rec, env
+}
+
+func TestRoutes(t *testing.T) {
+	tests := []struct {
+		method, target, body string
+		want                 int
+	}{
+		{"GET", "/health", "", http.StatusOK},
+		{"GET", BasePath + "/health", "", http.StatusOK},
+		{"GET", "/codes/lookup?code=76942", "", http.StatusOK},
+		{"GET", "/codes/lookup", "", http.StatusBadRequest},
+		{"POST", "/codes/batch", `{"codes": ["76942", "E0601"]}`, http.StatusOK},
+		{"POST", "/codes/batch", `{"codes": []}`, http.StatusBadRequest},
+		{"POST", "/codes/batch", `{`, http.StatusBadRequest},
+		{"GET", "/policies", "", http.StatusOK},
+		{"GET", "/policies/L33831", "", http.StatusOK},
+		{"GET", "/policies/l33831", "", http.StatusOK},
+		{"GET", "/policies/NOPE", "", http.StatusNotFound},
+		{"GET", "/policies/changes", "", http.StatusOK},
+		{"POST", "/policies/compare", `{"procedure_codes": ["76942"]}`, http.StatusOK},
+		{"POST", "/prior-auth/check", `{"procedure_codes": ["E0601"]}`, http.StatusOK},
+		{"POST", "/prior-auth/check", `{}`, http.StatusBadRequest},
+		{"GET", "/prior-auth/research/res_nope", "", http.StatusNotFound},
+		{"GET", "/coverage/criteria?q=sleep", "", http.StatusOK},
+		{"GET", "/coverage/criteria", "", http.StatusBadRequest},
+		{"POST", "/coverage/evaluate", `{"policy_id": "NOPE"}`, http.StatusNotFound},
+		{"GET", "/spending/by-code?code=E0601", "", http.StatusOK},
+		{"GET", "/spending/by-code", "", http.StatusBadRequest},
+		{"GET", "/jurisdictions", "", http.StatusOK},
+		{"GET", "/webhooks", "", http.StatusOK},
+		{"GET", "/nope", "", http.StatusNotFound},
+		{"DELETE", "/policies", "", http.StatusNotFound},
+	}
+
+	s := newServer(t, Options{})
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			rec, env := serve(t, s, tt.method, tt.target, tt.body)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if env.Success != (tt.want == http.StatusOK) {
+				t.Errorf("success = %v with status %d", env.Success, rec.Code)
+			}
+			if tt.want >= 400 && env.Error.Code == "" {
+				t.Errorf("error envelope has no code: %s", rec.Body)
+			}
+			if rec.Header().Get("X-Request-Id") == "" {
+				t.Error("no X-Request-Id header")
+			}
+		})
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	tests := []struct {
+		name   string
+		apiKey string
+		header string
+		want   int
+	}{
+		{"any key", "", "Bearer anything", http.StatusOK},
+		{"missing", "", "", http.StatusUnauthorized},
+		{"empty token", "", "Bearer ", http.StatusUnauthorized},
+		{"wrong scheme", "", "Basic dGVzdA==", http.StatusUnauthorized},
+		{"required key", "secret", "Bearer secret", http.StatusOK},
+		{"wrong key", "secret", "Bearer test", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer(t, Options{APIKey: tt.apiKey})
+			req := httptest.NewRequest("GET", "/health", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec, env := record(t, s, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusUnauthorized && env.Error.Code != "UNAUTHORIZED" {
+				t.Errorf("error code = %q, want UNAUTHORIZED", env.Error.Code)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	s := newServer(t, Options{RateLimit: 2})
+	for i := 0; i < 2; i++ {
+		if rec, _ := serve(t, s, "GET", "/health", ""); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i+1, rec.Code)
+		}
+	}
+	rec, env := serve(t, s, "GET", "/health", "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", rec.Code)
+	}
+	if env.Error.Code != "RATE_LIMITED" {
+		t.Errorf("error code = %q, want RATE_LIMITED", env.Error.Code)
+	}
+	if retry := rec.Header().Get("Retry-After"); retry == "" || retry == "0" {
+		t.Errorf("Retry-After = %q, want a positive number of seconds", retry)
+	}
+}
+
+func TestFailures(t *testing.T) {
+	s := newServer(t, Options{Failures: map[string]int{
+		"/policies":      500,
+		"/prior-auth/":   429,
+		"/webhooks/wh_1": 404,
+	}})
+	tests := []struct {
+		target    string
+		want      int
+		simulated bool
+	}{
+		{"/policies", 500, true},
+		{"/policies/L33831", 500, true},
+		{BasePath + "/policies", 500, true},
+		{"/policies-other", 404, false},
+		{"/prior-auth/research/res_1", 429, true},
+		{"/webhooks/wh_1", 404, true},
+		{"/webhooks", 200, false},
+		{"/health", 200, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec, env := serve(t, s, "GET", tt.target, "")
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if got := strings.HasPrefix(env.Error.Message, "Simulated failure"); got != tt.simulated {
+				t.Errorf("message = %q, simulated = %v, want %v", env.Error.Message, got, tt.simulated)
+			}
+			if tt.want == 429 && rec.Header().Get("Retry-After") == "" {
+				t.Error("429 without Retry-After")
+			}
+		})
+	}
+}
+
+func TestErrorRate(t *testing.T) {
+	s := newServer(t, Options{ErrorRate: 1})
+	rec, env := serve(t, s, "GET", "/health", "")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", rec.Code)
+	}
+	if env.Error.Code != "SERVICE_UNAVAILABLE" {
+		t.Errorf("error code = %q, want SERVICE_UNAVAILABLE", env.Error.Code)
+	}
+
+	s = newServer(t, Options{ErrorRate: 0})
+	for i := 0; i < 20; i++ {
+		if rec, _ := serve(t, s, "GET", "/health", ""); rec.Code != http.StatusOK {
+			t.Fatalf("status = %d with no error rate", rec.Code)
+		}
+	}
+}
+
+func TestETag(t *testing.T) {
+	s := newServer(t, Options{})
+	rec, _ := serve(t, s, "GET", "/jurisdictions", "")
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag on a GET response")
+	}
+
+	req := httptest.NewRequest("GET", "/jurisdictions", nil)
+	req.Header.Set("Authorization", "Bearer test")
+	req.Header.Set("If-None-Match", etag)
+	if rec, _ := record(t, s, req); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("matching If-None-Match: status = %d with %d bytes, want 304 and no body", rec.Code, rec.Body.Len())
+	}
+
+	req = httptest.NewRequest("GET", "/jurisdictions", nil)
+	req.Header.Set("Authorization", "Bearer test")
+	req.Header.Set("If-None-Match", `"stale"`)
+	if rec, _ := record(t, s, req); rec.Code != http.StatusOK {
+		t.Errorf("stale If-None-Match: status = %d, want 200", rec.Code)
+	}
+
+	if rec, _ := serve(t, s, "POST", "/prior-auth/check", `{"procedure_codes": ["E0601"]}`); rec.Header().Get("ETag") != "" {
+		t.Error("POST response has an ETag")
+	}
+}
+
+func TestListPolicies(t *testing.T) {
+	s := newServer(t, Options{})
+	var active, all int
+	for _, p := range s.fixtures.Policies {
+		all++
+		if p.Status == "active" {
+			active++
+		}
+	}
+
+	tests := []struct {
+		target    string
+		wantLen   int
+		wantTotal int
+		wantPages int
+	}{
+		{"/policies", all, all, 1},
+		{"/policies?status=active", active, active, 1},
+		{"/policies?status=all", all, all, 1},
+		{"/policies?limit=2", 2, all, (all + 1) / 2},
+		{"/policies?limit=2&page=2", 2, all, (all + 1) / 2},
+		{"/policies?limit=2&page=99", 0, all, (all + 1) / 2},
+		{"/policies?q=no+policy+says+this", 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec, env := serve(t, s, "GET", tt.target, "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d", rec.Code)
+			}
+			var policies []map[string]interface{}
+			if err := json.Unmarshal(env.Data, &policies); err != nil {
+				t.Fatal(err)
+			}
+			if len(policies) != tt.wantLen {
+				t.Errorf("got %d policies, want %d", len(policies), tt.wantLen)
+			}
+			if m := env.Meta; m.Total != tt.wantTotal || m.TotalPages != tt.wantPages {
+				t.Errorf("meta total = %d over %d pages, want %d over %d", m.Total, m.TotalPages, tt.wantTotal, tt.wantPages)
+			}
+			if wantMore := tt.wantLen > 0 && env.Meta.Page < tt.wantPages; env.Meta.HasMore != wantMore {
+				t.Errorf("has_more = %v, want %v", env.Meta.HasMore, wantMore)
+			}
+			for _, p := range policies {
+				if _, ok := p["criteria"]; ok {
+					t.Errorf("list includes criteria for %v", p["policy_id"])
+				}
+			}
+		})
+	}
+}
+
+func TestGetPolicyInclude(t *testing.T) {
+	s := newServer(t, Options{})
+	for _, include := range []string{"", "criteria", "criteria,codes"} {
+		_, env := serve(t, s, "GET", "/policies/L33831?include="+include, "")
+		var p map[string]interface{}
+		if err := json.Unmarshal(env.Data, &p); err != nil {
+			t.Fatal(err)
+		}
+		for _, field := range []string{"criteria", "codes"} {
+			_, got := p[field]
+			if want := strings.Contains(include, field); got != want {
+				t.Errorf("include=%q: has %s = %v, want %v", include, field, got, want)
+			}
+		}
+	}
+}
+
+func TestResearch(t *testing.T) {
+	s := newServer(t, Options{})
+	_, env := serve(t, s, "POST", "/prior-auth/research", `{"procedure_codes": ["E0601"]}`)
+	var task struct {
+		ResearchID string          `json:"research_id"`
+		Status     string          `json:"status"`
+		Result     json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(env.Data, &task); err != nil {
+		t.Fatal(err)
+	}
+	if task.Status != "pending" {
+		t.Fatalf("new task status = %q, want pending", task.Status)
+	}
+
+	for _, want := range []string{"processing", "completed", "completed"} {
+		_, env := serve(t, s, "GET", "/prior-auth/research/"+task.ResearchID, "")
+		if err := json.Unmarshal(env.Data, &task); err != nil {
+			t.Fatal(err)
+		}
+		if task.Status != want {
+			t.Fatalf("status = %q, want %q", task.Status, want)
+		}
+	}
+	if len(task.Result) == 0 {
+		t.Error("completed task has no result")
+	}
+
+	_, env = serve(t, s, "POST", "/prior-auth/research", `{"procedure_codes": ["E0601"], "sync": true}`)
+	if err := json.Unmarshal(env.Data, &task); err != nil {
+		t.Fatal(err)
+	}
+	if task.Status != "completed" {
+		t.Errorf("sync task status = %q, want completed", task.Status)
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	s := newServer(t, Options{})
+	count := func() int {
+		_, env := serve(t, s, "GET", "/webhooks", "")
+		var hooks []json.RawMessage
+		if err := json.Unmarshal(env.Data, &hooks); err != nil {
+			t.Fatal(err)
+		}
+		return len(hooks)
+	}
+	before := count()
+
+	rec, env := serve(t, s, "POST", "/webhooks", `{"url": "https://example.com/hook", "events": ["policy.updated"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create: status = %d", rec.Code)
+	}
+	var hook struct {
+		ID     string `json:"id"`
+		URL    string `json:"url"`
+		Secret string `json:"secret"`
+	}
+	if err := json.Unmarshal(env.Data, &hook); err != nil {
+		t.Fatal(err)
+	}
+	if hook.Secret == "" {
+		t.Error("create did not return the secret")
+	}
+	if count() != before+1 {
+		t.Errorf("webhook count after create = %d, want %d", count(), before+1)
+	}
+
+	_, env = serve(t, s, "PATCH", "/webhooks/"+hook.ID, `{"url": "https://example.com/new"}`)
+	hook.Secret = ""
+	if err := json.Unmarshal(env.Data, &hook); err != nil {
+		t.Fatal(err)
+	}
+	if hook.URL != "https://example.com/new" || hook.Secret != "" {
+		t.Errorf("update returned url %q, secret %q", hook.URL, hook.Secret)
+	}
+
+	if rec, _ := serve(t, s, "POST", "/webhooks/"+hook.ID+"/test", ""); rec.Code != http.StatusOK {
+		t.Errorf("test: status = %d", rec.Code)
+	}
+	if rec, _ := serve(t, s, "DELETE", "/webhooks/"+hook.ID, ""); rec.Code != http.StatusOK {
+		t.Errorf("delete: status = %d", rec.Code)
+	}
+	if count() != before {
+		t.Errorf("webhook count after delete = %d, want %d", count(), before)
+	}
+	for _, method := range []string{"PATCH", "DELETE"} {
+		if rec, _ := serve(t, s, method, "/webhooks/"+hook.ID, `{}`); rec.Code != http.StatusNotFound {
+			t.Errorf("%s deleted webhook: status = %d, want 404", method, rec.Code)
+		}
+	}
+}
+
+func TestLoadFixturesOverride(t *testing.T) {
+	dir := t.TempDir()
+	override := `[{"jurisdiction_code": "ZZ", "mac_name": "Test MAC", "states": ["XX"]}]`
+	if err := os.WriteFile(filepath.Join(dir, "jurisdictions.json"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := LoadFixtures(dir)
+	if err != nil {
+		t.Fatalf("LoadFixtures: %v", err)
+	}
+	if len(f.Jurisdictions) != 1 || f.Jurisdictions[0].JurisdictionCode != "ZZ" {
+		t.Errorf("jurisdictions = %+v, want the override", f.Jurisdictions)
+	}
+	builtin, err := LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Policies) != len(builtin.Policies) || len(f.Policies) == 0 {
+		t.Errorf("got %d policies, want the %d built-in ones", len(f.Policies), len(builtin.Policies))
+	}
+
+	s := NewServer(f, Options{})
+	_, env := serve(t, s, "GET", "/jurisdictions", "")
+	if !strings.Contains(string(env.Data), `"ZZ"`) {
+		t.Errorf("server answered %s, want the override", env.Data)
+	}
+}
+
+func TestLoadFixturesInvalid(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "policies.json"), []byte(`{"not": "a list"`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFixtures(dir); err == nil || !strings.Contains(err.Error(), "policies.json") {
+		t.Errorf("LoadFixtures error = %v, want one naming policies.json", err)
+	}
+}
+
+func TestWriteFixtures(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "codes.json")
+	if err := os.WriteFile(existing, []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := WriteFixtures(dir)
+	if err != nil {
+		t.Fatalf("WriteFixtures: %v", err)
+	}
+	entries, err := builtin.ReadDir("fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != len(entries)-1 {
+		t.Errorf("wrote %d files, want %d", len(written), len(entries)-1)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != `{}` {
+		t.Errorf("existing file was overwritten with %q", data)
+	}
+
+	// The written set loads back as it was embedded.
+	if _, err := LoadFixtures(dir); err != nil {
+		t.Errorf("LoadFixtures of written fixtures: %v", err)
+	}
+	if written, err := WriteFixtures(dir); err != nil || len(written) != 0 {
+		t.Errorf("second WriteFixtures wrote %v, %v; want nothing", written, err)
+	}
+}
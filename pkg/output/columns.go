@@ -0,0 +1,245 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Column is one column of a list command's table.
+type Column struct {
+	// Field is the flattened record key, the same name used as the csv
+	// header, such as policy_id or rvu.work_rvu.
+	Field string
+	// Header defaults to Field in upper case.
+	Header string
+	// Wide columns are only shown with --wide.
+	Wide bool
+	// Link, if set, maps a cell to a URL for formats that support links.
+	Link func(value string) string
+}
+
+func (c Column) header() string {
+	if c.Header != "" {
+		return c.Header
+	}
+	return strings.ToUpper(c.Field)
+}
+
+const (
+	columnGap = 2
+	// minColumnWidth is how narrow a column may be squeezed to fit the
+	// terminal, unless its content is already narrower.
+	minColumnWidth = 6
+)
+
+// writeGrid renders records as an aligned table. defaults are the
+// command's columns; with none, every flattened field is shown.
+func writeGrid(w io.Writer, records interface{}, defaults []Column, empty string, opts *Options) error {
+	columns, cells, err := gridCells(records, defaults, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(cells) == 0 {
+		if empty != "" {
+			fmt.Fprintln(w, empty)
+		}
+		return nil
+	}
+
+	widths := make([]int, len(columns))
+	floors := make([]int, len(columns))
+	numeric := make([]bool, len(columns))
+	for j, col := range columns {
+		if !opts.NoHeaders {
+			widths[j] = utf8.RuneCountInString(col.header())
+		}
+		numeric[j] = true
+		hasValue := false
+		for i := range cells {
+			widths[j] = max(widths[j], utf8.RuneCountInString(cells[i][j]))
+			if cells[i][j] == "" {
+				continue
+			}
+			hasValue = true
+			if _, err := strconv.ParseFloat(cells[i][j], 64); err != nil {
+				numeric[j] = false
+			}
+		}
+		numeric[j] = numeric[j] && hasValue
+		floors[j] = min(widths[j], max(minColumnWidth, utf8.RuneCountInString(col.header())))
+	}
+	fitWidths(widths, floors, opts.Width)
+
+	if !opts.NoHeaders {
+		headers := make([]string, len(columns))
+		for j, col := range columns {
+			headers[j] = col.header()
+		}
+		writeGridLine(w, headers, widths, numeric)
+	}
+	for _, line := range cells {
+		writeGridLine(w, line, widths, numeric)
+	}
+	return nil
+}
+
+// gridCells flattens records into the rows and columns selected by
+// --columns, --wide and --sort-by.
+func gridCells(records interface{}, defaults []Column, opts *Options) ([]Column, [][]string, error) {
+	fields, rows := Flatten(records)
+
+	columns, err := selectColumns(fields, defaults, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := sortRows(rows, fields, defaults, opts.SortBy); err != nil {
+		return nil, nil, err
+	}
+
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		cells[i] = make([]string, len(columns))
+		for j, col := range columns {
+			cells[i][j] = cleanCell(row[col.Field])
+		}
+	}
+	return columns, cells, nil
+}
+
+// selectColumns resolves --columns and --wide against the command's
+// columns. --columns may name any flattened field, not just the defaults.
+func selectColumns(fields []string, defaults []Column, opts *Options) ([]Column, error) {
+	if len(opts.Columns) > 0 {
+		columns := make([]Column, 0, len(opts.Columns))
+		for _, name := range opts.Columns {
+			col, ok := findColumn(name, fields, defaults)
+			if !ok {
+				return nil, unknownColumn(name, fields)
+			}
+			columns = append(columns, col)
+		}
+		return columns, nil
+	}
+
+	if len(defaults) == 0 {
+		columns := make([]Column, len(fields))
+		for i, field := range fields {
+			columns[i] = Column{Field: field}
+		}
+		return columns, nil
+	}
+
+	var columns []Column
+	for _, col := range defaults {
+		if !col.Wide || opts.Wide {
+			columns = append(columns, col)
+		}
+	}
+	return columns, nil
+}
+
+// findColumn matches name, case-insensitively, against the command's
+// columns by field or header, then against the flattened fields.
+func findColumn(name string, fields []string, defaults []Column) (Column, bool) {
+	for _, col := range defaults {
+		if strings.EqualFold(name, col.Field) || strings.EqualFold(name, col.header()) {
+			return col, true
+		}
+	}
+	for _, field := range fields {
+		if strings.EqualFold(name, field) {
+			return Column{Field: field}, true
+		}
+	}
+	return Column{}, false
+}
+
+func unknownColumn(name string, fields []string) error {
+	return &UnsupportedError{
+		Format: "table",
+		Reason: fmt.Sprintf("has no column %q (available: %s)", name, strings.Join(fields, ", ")),
+	}
+}
+
+// sortRows orders rows by key, numerically when both cells are numbers. A
+// leading "-" sorts in descending order.
+func sortRows(rows []map[string]string, fields []string, defaults []Column, key string) error {
+	if key == "" {
+		return nil
+	}
+	desc := strings.HasPrefix(key, "-")
+	name := strings.TrimPrefix(key, "-")
+	col, ok := findColumn(name, fields, defaults)
+	if !ok {
+		return unknownColumn(name, fields)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i][col.Field], rows[j][col.Field]
+		if desc {
+			a, b = b, a
+		}
+		x, errX := strconv.ParseFloat(a, 64)
+		y, errY := strconv.ParseFloat(b, 64)
+		if errX == nil && errY == nil {
+			return x < y
+		}
+		return a < b
+	})
+	return nil
+}
+
+// fitWidths narrows the widest columns, one rune at a time, until the
+// table fits in total. A total of 0 means unlimited.
+func fitWidths(widths, floors []int, total int) {
+	if total <= 0 {
+		return
+	}
+	for {
+		used := columnGap * (len(widths) - 1)
+		for _, w := range widths {
+			used += w
+		}
+		if used <= total {
+			return
+		}
+
+		widest := -1
+		for j, w := range widths {
+			if w > floors[j] && (widest < 0 || w > widths[widest]) {
+				widest = j
+			}
+		}
+		if widest < 0 {
+			return
+		}
+		widths[widest]--
+	}
+}
+
+func writeGridLine(w io.Writer, cells []string, widths []int, numeric []bool) {
+	var b strings.Builder
+	for j, cell := range cells {
+		if j > 0 {
+			b.WriteString(strings.Repeat(" ", columnGap))
+		}
+		cell = Truncate(widths[j], cell)
+		pad := strings.Repeat(" ", widths[j]-utf8.RuneCountInString(cell))
+		if numeric[j] {
+			b.WriteString(pad + cell)
+		} else {
+			b.WriteString(cell + pad)
+		}
+	}
+	fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+}
+
+// cleanCell keeps multi-line values such as criteria text on one row.
+func cleanCell(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,78 @@
+package output
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register("csv", FormatterFunc(formatCSV))
+	Register("tsv", FormatterFunc(formatTSV))
+}
+
+// formatCSV writes Records as a header row followed by one row per record,
+// quoting cells as needed.
+func formatCSV(w io.Writer, v *View, opts *Options) error {
+	columns, rows, err := delimitedRows("csv", v)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// tsvEscaper writes the characters that would break a TSV row as escape
+// sequences, as PostgreSQL's text format and most TSV readers expect.
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// formatTSV writes Records like formatCSV, but tab separated and with tabs,
+// newlines and backslashes escaped instead of quoted, so every line is one
+// record for cut and awk.
+func formatTSV(w io.Writer, v *View, opts *Options) error {
+	columns, rows, err := delimitedRows("tsv", v)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, row := range append([][]string{columns}, rows...) {
+		for i, text := range row {
+			if i > 0 {
+				bw.WriteByte('\t')
+			}
+			tsvEscaper.WriteString(bw, text)
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// delimitedRows flattens Records into a header and rows of cells. Lists of
+// objects are spread over indexed columns rather than written as JSON.
+func delimitedRows(name string, v *View) ([]string, [][]string, error) {
+	if v.Records == nil {
+		return nil, nil, &UnsupportedError{Format: name, Reason: "is only available for list commands"}
+	}
+
+	columns, flat := flattenExpanded(v.Records)
+	rows := make([][]string, len(flat))
+	for i, row := range flat {
+		rows[i] = make([]string, len(columns))
+		for j, col := range columns {
+			rows[i][j] = row[col]
+		}
+	}
+	return columns, rows, nil
+}
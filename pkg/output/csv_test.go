@@ -0,0 +1,60 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+type testYear struct {
+	Year int     `json:"year"`
+	Paid float64 `json:"paid"`
+}
+
+type testRecord struct {
+	Code   string     `json:"code"`
+	Note   string     `json:"note"`
+	States []string   `json:"states"`
+	ByYear []testYear `json:"by_year"`
+	Count  int        `json:"count"`
+}
+
+func TestDelimited(t *testing.T) {
+	records := []testRecord{
+		{Code: "E0601", Note: "a, \"quoted\" note", States: []string{"TX", "OK"}, ByYear: []testYear{{2022, 1.5}}, Count: 2},
+		{Code: "99213", Note: "tab\there\nnewline \\ slash", ByYear: []testYear{{2022, 3}, {2023, 4}}, Count: 1},
+	}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"csv", `code,note,states,by_year.0.year,by_year.0.paid,by_year.1.year,by_year.1.paid,count
+E0601,"a, ""quoted"" note",TX;OK,2022,1.5,,,2
+99213,"tab	here
+newline \ slash",,2022,3,2023,4,1
+`},
+		{"tsv", "code\tnote\tstates\tby_year.0.year\tby_year.0.paid\tby_year.1.year\tby_year.1.paid\tcount\n" +
+			"E0601\ta, \"quoted\" note\tTX;OK\t2022\t1.5\t\t\t2\n" +
+			"99213\ttab\\there\\nnewline \\\\ slash\t\t2022\t3\t2023\t4\t1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, tt.format, &View{Records: records}, nil); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDelimitedNeedsRecords(t *testing.T) {
+	for _, format := range []string{"csv", "tsv"} {
+		var buf bytes.Buffer
+		err := Render(&buf, format, &View{Data: map[string]string{"a": "b"}}, nil)
+		if _, ok := err.(*UnsupportedError); !ok {
+			t.Errorf("%s without records: error = %v, want an UnsupportedError", format, err)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package output
+
+// Document is a format-neutral layout of a single result, for formats that
+// produce reports rather than data, such as markdown.
+type Document struct {
+	Title  string
+	Blocks []Block
+}
+
+// Add appends blocks to the document.
+func (d *Document) Add(blocks ...Block) {
+	d.Blocks = append(d.Blocks, blocks...)
+}
+
+// Block is one part of a Document: a Heading, Paragraph, Fields, List,
+// Checklist or Table.
+type Block interface {
+	block()
+}
+
+// Span is a run of text, shown as a link when URL is set.
+type Span struct {
+	Text string
+	URL  string
+}
+
+// Text is inline content made of spans.
+type Text []Span
+
+// Plain returns s as unlinked text.
+func Plain(s string) Text {
+	return Text{{Text: s}}
+}
+
+// Link returns s linked to url, or plain text when url is empty.
+func Link(s, url string) Text {
+	return Text{{Text: s, URL: url}}
+}
+
+// String returns the text without links.
+func (t Text) String() string {
+	var s string
+	for _, span := range t {
+		s += span.Text
+	}
+	return s
+}
+
+// Tone marks a field's value as good, bad or a warning, such as a coverage
+// verdict. Formats may highlight it.
+type Tone int
+
+const (
+	ToneNone Tone = iota
+	ToneGood
+	ToneWarn
+	ToneBad
+)
+
+// Heading starts a section. Level 2 is a top-level section; the title is
+// level 1.
+type Heading struct {
+	Level int
+	Text  string
+}
+
+// Paragraph is free text, such as a policy description.
+type Paragraph struct {
+	Text Text
+}
+
+// Fields is a list of labelled values.
+type Fields []Field
+
+// Field is one labelled value. Fields with an empty value are skipped.
+type Field struct {
+	Label string
+	Value Text
+	Tone  Tone
+}
+
+// List is a bulleted list.
+type List []Text
+
+// Checklist is a list of items to tick off, such as required documentation.
+type Checklist []Text
+
+// Table is a grid with a header row.
+type Table struct {
+	Headers []string
+	Rows    [][]Text
+}
+
+func (Heading) block()   {}
+func (Paragraph) block() {}
+func (Fields) block()    {}
+func (List) block()      {}
+func (Checklist) block() {}
+func (Table) block()     {}
+
+// genericDocument lays out views without a Document of their own: list
+// results as a table of their columns, single objects as fields and
+// anything else as a paragraph.
+func genericDocument(v *View, opts *Options) (*Document, error) {
+	doc := &Document{}
+
+	if v.Columns != nil {
+		columns, cells, err := gridCells(v.Payload(), v.Columns, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(cells) == 0 {
+			if v.Empty != "" {
+				doc.Add(Paragraph{Text: Plain(v.Empty)})
+			}
+			return doc, nil
+		}
+		doc.Add(gridTable(columns, cells))
+		return doc, nil
+	}
+
+	payload, err := Normalize(v.Payload())
+	if err != nil {
+		return nil, err
+	}
+
+	switch p := payload.(type) {
+	case []interface{}:
+		if isScalarList(p) {
+			list := List{}
+			for _, item := range p {
+				list = append(list, Plain(scalarText(item)))
+			}
+			doc.Add(list)
+			return doc, nil
+		}
+		columns, cells, err := gridCells(p, nil, opts)
+		if err != nil {
+			return nil, err
+		}
+		doc.Add(gridTable(columns, cells))
+
+	case map[string]interface{}:
+		columns, rows := Flatten(p)
+		fields := Fields{}
+		for _, col := range columns {
+			fields = append(fields, Field{Label: col, Value: Plain(rows[0][col])})
+		}
+		doc.Add(fields)
+
+	default:
+		doc.Add(Paragraph{Text: Plain(scalarText(payload))})
+	}
+	return doc, nil
+}
+
+func gridTable(columns []Column, cells [][]string) Table {
+	t := Table{Headers: make([]string, len(columns))}
+	for j, col := range columns {
+		t.Headers[j] = col.header()
+	}
+	for _, line := range cells {
+		row := make([]Text, len(columns))
+		for j, cell := range line {
+			row[j] = Plain(cell)
+			if columns[j].Link != nil && cell != "" {
+				row[j] = Link(cell, columns[j].Link(cell))
+			}
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
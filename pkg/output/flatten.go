@@ -0,0 +1,345 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// listSeparator joins slices of scalars, such as a jurisdiction's states,
+// into a single cell.
+const listSeparator = ";"
+
+// Flatten turns records (a slice, or a single value treated as one record)
+// into rows of cells keyed by dotted column names such as rvu.work_rvu.
+// Columns follow struct field order, so they are stable from one run to the
+// next even when a field is empty; for plain maps they are sorted.
+func Flatten(records interface{}) ([]string, []map[string]string) {
+	return textRows(flattenCells(records))
+}
+
+// textRows drops the types from flattened cells.
+func textRows(columns []string, cells []map[string]cell) ([]string, []map[string]string) {
+	rows := make([]map[string]string, len(cells))
+	for i, row := range cells {
+		rows[i] = make(map[string]string, len(row))
+		for key, c := range row {
+			rows[i][key] = c.text
+		}
+	}
+	return columns, rows
+}
+
+// cellKind is the type of a flattened value, for formats with typed cells.
+type cellKind int
+
+const (
+	textCell cellKind = iota
+	numberCell
+	boolCell
+)
+
+// cell is a flattened value. num is set for numberCell.
+type cell struct {
+	text string
+	kind cellKind
+	num  float64
+}
+
+// decimal is implemented by string-encoded numbers such as client.Decimal.
+type decimal interface {
+	Float64() (float64, bool)
+}
+
+// flattenCells is Flatten keeping each value's type.
+func flattenCells(records interface{}) ([]string, []map[string]cell) {
+	return (&flattener{seen: map[string]bool{}}).records(records)
+}
+
+// flattenExpanded is Flatten with lists of objects spread over indexed
+// columns, such as by_year.0.year and by_year.1.year, instead of kept as
+// JSON in a single cell. It suits formats read by other programs.
+func flattenExpanded(records interface{}) ([]string, []map[string]string) {
+	return textRows((&flattener{seen: map[string]bool{}, expandLists: true}).records(records))
+}
+
+func (f *flattener) records(records interface{}) ([]string, []map[string]cell) {
+
+	v := reflect.ValueOf(records)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	var rows []map[string]cell
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		if v.Len() == 0 && v.Type().Elem().Kind() != reflect.Interface {
+			// Still produce the header for an empty typed list.
+			f.flatten("", reflect.Value{}, v.Type().Elem(), map[string]cell{})
+		}
+		for i := 0; i < v.Len(); i++ {
+			row := map[string]cell{}
+			f.flatten("", v.Index(i), v.Type().Elem(), row)
+			rows = append(rows, row)
+		}
+	} else {
+		row := map[string]cell{}
+		f.flatten("", v, v.Type(), row)
+		rows = append(rows, row)
+	}
+	return f.columns, rows
+}
+
+type flattener struct {
+	columns []string
+	seen    map[string]bool
+	// expandLists spreads lists of objects over indexed columns.
+	expandLists bool
+}
+
+func (f *flattener) set(row map[string]cell, key string, value cell) {
+	if key == "" {
+		key = "value"
+	}
+	if !f.seen[key] {
+		f.seen[key] = true
+		i := f.position(key)
+		f.columns = append(f.columns[:i], append([]string{key}, f.columns[i:]...)...)
+	}
+	row[key] = value
+}
+
+// position returns where a new column goes: at the end, except that the
+// columns of a list element go after those of the same list already seen,
+// so a record with a longer list than the ones before it keeps its list's
+// columns together.
+func (f *flattener) position(key string) int {
+	parts := strings.Split(key, ".")
+	for j := len(parts) - 1; j > 0; j-- {
+		if _, err := strconv.Atoi(parts[j]); err != nil {
+			continue
+		}
+		list := strings.Join(parts[:j], ".") + "."
+		for i := len(f.columns) - 1; i >= 0; i-- {
+			if strings.HasPrefix(f.columns[i], list) {
+				return i + 1
+			}
+		}
+		break
+	}
+	return len(f.columns)
+}
+
+// flatten writes v into row. t is v's static type; v may be invalid (a nil
+// pointer), in which case the columns are still registered from t.
+func (f *flattener) flatten(prefix string, v reflect.Value, t reflect.Type, row map[string]cell) {
+	switch t.Kind() {
+	case reflect.Pointer:
+		if v.IsValid() && !v.IsNil() {
+			f.flatten(prefix, v.Elem(), t.Elem(), row)
+		} else {
+			f.flatten(prefix, reflect.Value{}, t.Elem(), row)
+		}
+
+	case reflect.Interface:
+		if v.IsValid() && !v.IsNil() {
+			f.flatten(prefix, v.Elem(), v.Elem().Type(), row)
+		} else {
+			f.set(row, prefix, cell{})
+		}
+
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, inline := jsonFieldName(field)
+			if name == "-" {
+				continue
+			}
+
+			var fv reflect.Value
+			if v.IsValid() {
+				fv = v.Field(i)
+			}
+			if inline {
+				f.flatten(prefix, fv, field.Type, row)
+			} else {
+				f.flatten(joinKey(prefix, name), fv, field.Type, row)
+			}
+		}
+
+	case reflect.Map:
+		if !v.IsValid() || v.Len() == 0 || t.Key().Kind() != reflect.String {
+			f.set(row, prefix, cell{text: encodeCell(v)})
+			return
+		}
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			f.flatten(joinKey(prefix, k), v.MapIndex(reflect.ValueOf(k).Convert(t.Key())), t.Elem(), row)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if f.expandLists && isObjectList(v) {
+			for i := 0; i < v.Len(); i++ {
+				f.flatten(joinKey(prefix, strconv.Itoa(i)), v.Index(i), t.Elem(), row)
+			}
+			return
+		}
+		f.set(row, prefix, cell{text: formatList(v)})
+
+	default:
+		f.set(row, prefix, scalarCell(v))
+	}
+}
+
+func jsonFieldName(field reflect.StructField) (name string, inline bool) {
+	tag := field.Tag.Get("json")
+	name, _, _ = strings.Cut(tag, ",")
+	if name == "" {
+		if field.Anonymous && indirectType(field.Type).Kind() == reflect.Struct {
+			return "", true
+		}
+		name = field.Name
+	}
+	return name, false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// formatList joins a slice of scalars with listSeparator. Slices of objects
+// do not fit in a cell, so they are kept as compact JSON.
+func formatList(v reflect.Value) string {
+	if !v.IsValid() || v.Len() == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		for elem.Kind() == reflect.Interface || elem.Kind() == reflect.Pointer {
+			if elem.IsNil() {
+				break
+			}
+			elem = elem.Elem()
+		}
+		switch elem.Kind() {
+		case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+			return encodeCell(v)
+		}
+		parts = append(parts, formatScalar(elem))
+	}
+	return strings.Join(parts, listSeparator)
+}
+
+// isObjectList reports whether v is a non-empty list whose elements are
+// all objects.
+func isObjectList(v reflect.Value) bool {
+	if !v.IsValid() || v.Len() == 0 {
+		return false
+	}
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		for elem.Kind() == reflect.Interface || elem.Kind() == reflect.Pointer {
+			if elem.IsNil() {
+				return false
+			}
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct && elem.Kind() != reflect.Map {
+			return false
+		}
+	}
+	return true
+}
+
+// scalarCell types a scalar: Go numbers and decimals are numbers, bools are
+// bools and everything else, including numeric-looking strings such as
+// procedure codes, is text.
+func scalarCell(v reflect.Value) cell {
+	c := cell{text: formatScalar(v)}
+	if !v.IsValid() || c.text == "" {
+		return c
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		f, _ := strconv.ParseFloat(c.text, 64)
+		c.kind, c.num = numberCell, f
+	case reflect.Bool:
+		c.kind = boolCell
+	case reflect.Interface, reflect.Pointer:
+		if !v.IsNil() {
+			return scalarCell(v.Elem())
+		}
+	default:
+		if d, ok := v.Interface().(decimal); ok {
+			if f, ok := d.Float64(); ok {
+				c.kind, c.num = numberCell, f
+			}
+		}
+	}
+	return c
+}
+
+func formatScalar(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return ""
+		}
+		return formatScalar(v.Elem())
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func encodeCellValue(v interface{}) string {
+	return encodeCell(reflect.ValueOf(v))
+}
+
+func encodeCell(v reflect.Value) string {
+	if !v.IsValid() || ((v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && v.IsNil()) {
+		return ""
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprint(v.Interface())
+	}
+	return string(data)
+}
@@ -0,0 +1,124 @@
+package output
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testRVU struct {
+	Work     float64 `json:"work_rvu"`
+	Facility float64 `json:"facility_rvu"`
+}
+
+type Audit struct {
+	Source string `json:"source"`
+}
+
+type testCode struct {
+	Audit
+	Code     string            `json:"code"`
+	RVU      *testRVU          `json:"rvu"`
+	Tags     map[string]string `json:"tags"`
+	Years    []testYear        `json:"years"`
+	Active   bool              `json:"active"`
+	internal string
+	Skipped  string `json:"-"`
+}
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		name    string
+		records interface{}
+		columns []string
+		rows    []map[string]string
+	}{
+		{
+			"struct fields in order, nested and inline",
+			[]testCode{{
+				Audit:    Audit{Source: "cms"},
+				Code:     "76942",
+				RVU:      &testRVU{Work: 0.67, Facility: 1.5},
+				Tags:     map[string]string{"b": "2", "a": "1"},
+				Years:    []testYear{{2023, 4}},
+				Active:   true,
+				internal: "x",
+				Skipped:  "x",
+			}},
+			[]string{"source", "code", "rvu.work_rvu", "rvu.facility_rvu", "tags.a", "tags.b", "years", "active"},
+			[]map[string]string{{
+				"source": "cms", "code": "76942", "rvu.work_rvu": "0.67", "rvu.facility_rvu": "1.5",
+				"tags.a": "1", "tags.b": "2", "years": `[{"year":2023,"paid":4}]`, "active": "true",
+			}},
+		},
+		{
+			"nil pointer still has its columns",
+			[]testCode{{Code: "E0601"}},
+			[]string{"source", "code", "rvu.work_rvu", "rvu.facility_rvu", "tags", "years", "active"},
+			[]map[string]string{{
+				"source": "", "code": "E0601", "rvu.work_rvu": "", "rvu.facility_rvu": "",
+				"tags": "", "years": "", "active": "false",
+			}},
+		},
+		{
+			"empty typed list has a header",
+			[]testRVU{},
+			[]string{"work_rvu", "facility_rvu"},
+			nil,
+		},
+		{
+			"single value is one record",
+			testRVU{Work: 1},
+			[]string{"work_rvu", "facility_rvu"},
+			[]map[string]string{{"work_rvu": "1", "facility_rvu": "0"}},
+		},
+		{
+			"plain maps with sorted keys",
+			[]interface{}{
+				map[string]interface{}{"state": "TX", "codes": []interface{}{"A", "B"}},
+				map[string]interface{}{"state": "OK", "extra": nil},
+			},
+			[]string{"codes", "state", "extra"},
+			[]map[string]string{
+				{"codes": "A;B", "state": "TX"},
+				{"state": "OK", "extra": ""},
+			},
+		},
+		{
+			"scalars",
+			[]string{"a", "b"},
+			[]string{"value"},
+			[]map[string]string{{"value": "a"}, {"value": "b"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, rows := Flatten(tt.records)
+			if !reflect.DeepEqual(columns, tt.columns) {
+				t.Errorf("columns = %q, want %q", columns, tt.columns)
+			}
+			if len(rows) != len(tt.rows) || (len(rows) > 0 && !reflect.DeepEqual(rows, tt.rows)) {
+				t.Errorf("rows = %q, want %q", rows, tt.rows)
+			}
+		})
+	}
+}
+
+func TestFlattenExpanded(t *testing.T) {
+	records := []testCode{
+		{Code: "A", Years: []testYear{{2022, 1}}},
+		{Code: "B", Years: []testYear{{2022, 2}, {2023, 3}}},
+	}
+	columns, rows := flattenExpanded(records)
+
+	want := []string{"source", "code", "rvu.work_rvu", "rvu.facility_rvu", "tags",
+		"years.0.year", "years.0.paid", "years.1.year", "years.1.paid", "active"}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("columns = %q, want %q", columns, want)
+	}
+	if got := rows[1]["years.1.paid"]; got != "3" {
+		t.Errorf("second record's years.1.paid = %q, want 3", got)
+	}
+	if _, ok := rows[0]["years.1.year"]; ok {
+		t.Error("first record has a cell for a list element it does not have")
+	}
+}
@@ -0,0 +1,154 @@
+package output
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register("html", FormatterFunc(formatHTML))
+}
+
+// htmlStyle is inlined so reports open offline and print cleanly.
+const htmlStyle = `
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; max-width: 960px; margin: 2rem auto; padding: 0 1.5rem; line-height: 1.5; }
+h1 { font-size: 1.6rem; border-bottom: 2px solid #d0d7de; padding-bottom: .4rem; }
+h2 { font-size: 1.2rem; margin-top: 2rem; border-bottom: 1px solid #d0d7de; padding-bottom: .2rem; }
+h3 { font-size: 1rem; margin-top: 1.2rem; }
+dl.fields { display: grid; grid-template-columns: max-content 1fr; gap: .3rem 1.2rem; }
+dl.fields dt { font-weight: 600; }
+dl.fields dd { margin: 0; }
+table { border-collapse: collapse; width: 100%; margin: .5rem 0; font-size: .92rem; }
+th, td { border: 1px solid #d0d7de; padding: .35rem .6rem; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+ul.checklist { list-style: none; padding-left: .2rem; }
+ul.checklist li::before { content: ""; display: inline-block; width: .8rem; height: .8rem; border: 1.5px solid #57606a; border-radius: 2px; margin-right: .6rem; vertical-align: -.1rem; }
+.tone { font-weight: 700; padding: .05rem .5rem; border-radius: 1rem; }
+.good { background: #dafbe1; color: #116329; }
+.warn { background: #fff8c5; color: #7d4e00; }
+.bad { background: #ffebe9; color: #a40e26; }
+a { color: #0969da; }
+footer { margin-top: 3rem; padding-top: .6rem; border-top: 1px solid #d0d7de; color: #57606a; font-size: .85rem; }
+footer dl.fields { gap: .1rem 1rem; }
+@media print { body { margin: 0; max-width: none; } a { color: inherit; } }
+`
+
+// formatHTML writes a self-contained HTML report: one file with inline CSS
+// and no external resources, ending with a footer that records the request
+// parameters and when the report was generated.
+func formatHTML(w io.Writer, v *View, opts *Options) error {
+	doc, err := viewDocument(v, opts)
+	if err != nil {
+		return err
+	}
+
+	title := doc.Title
+	if title == "" {
+		title = "Verity Report"
+	}
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n<style>%s</style>\n</head>\n<body>\n", html.EscapeString(title), htmlStyle)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", html.EscapeString(title))
+
+	for _, block := range doc.Blocks {
+		writeHTMLBlock(&b, block)
+	}
+
+	b.WriteString("<footer>\n")
+	if len(opts.Params) > 0 {
+		writeHTMLBlock(&b, opts.Params)
+	}
+	fmt.Fprintf(&b, "<p>Generated by Verity CLI on %s</p>\n", time.Now().Format("2006-01-02 15:04:05 MST"))
+	b.WriteString("</footer>\n</body>\n</html>\n")
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+func writeHTMLBlock(b *strings.Builder, block Block) {
+	switch bl := block.(type) {
+	case Heading:
+		level := min(max(bl.Level, 2), 6)
+		fmt.Fprintf(b, "<h%d>%s</h%d>\n", level, html.EscapeString(bl.Text), level)
+
+	case Paragraph:
+		// Keep the line breaks of multi-line text such as criteria.
+		text := strings.ReplaceAll(htmlText(bl.Text), "\n", "<br>\n")
+		fmt.Fprintf(b, "<p>%s</p>\n", text)
+
+	case Fields:
+		b.WriteString("<dl class=\"fields\">\n")
+		for _, f := range bl {
+			if f.Value.String() == "" {
+				continue
+			}
+			value := htmlText(f.Value)
+			if class := toneClass(f.Tone); class != "" {
+				value = fmt.Sprintf("<span class=\"tone %s\">%s</span>", class, value)
+			}
+			fmt.Fprintf(b, "<dt>%s</dt><dd>%s</dd>\n", html.EscapeString(f.Label), value)
+		}
+		b.WriteString("</dl>\n")
+
+	case List:
+		b.WriteString("<ul>\n")
+		for _, item := range bl {
+			fmt.Fprintf(b, "<li>%s</li>\n", htmlText(item))
+		}
+		b.WriteString("</ul>\n")
+
+	case Checklist:
+		b.WriteString("<ul class=\"checklist\">\n")
+		for _, item := range bl {
+			fmt.Fprintf(b, "<li>%s</li>\n", htmlText(item))
+		}
+		b.WriteString("</ul>\n")
+
+	case Table:
+		b.WriteString("<table>\n<thead><tr>")
+		for _, h := range bl.Headers {
+			fmt.Fprintf(b, "<th>%s</th>", html.EscapeString(h))
+		}
+		b.WriteString("</tr></thead>\n<tbody>\n")
+		for _, row := range bl.Rows {
+			b.WriteString("<tr>")
+			for _, cell := range row {
+				fmt.Fprintf(b, "<td>%s</td>", htmlText(cell))
+			}
+			b.WriteString("</tr>\n")
+		}
+		b.WriteString("</tbody>\n</table>\n")
+	}
+}
+
+func htmlText(t Text) string {
+	var b strings.Builder
+	for _, span := range t {
+		text := html.EscapeString(span.Text)
+		// Only web links; anything else, such as javascript:, stays text.
+		if strings.HasPrefix(span.URL, "https://") || strings.HasPrefix(span.URL, "http://") {
+			fmt.Fprintf(&b, "<a href=\"%s\">%s</a>", html.EscapeString(span.URL), text)
+		} else {
+			b.WriteString(text)
+		}
+	}
+	return b.String()
+}
+
+func toneClass(t Tone) string {
+	switch t {
+	case ToneGood:
+		return "good"
+	case ToneWarn:
+		return "warn"
+	case ToneBad:
+		return "bad"
+	}
+	return ""
+}
@@ -0,0 +1,20 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+func init() {
+	Register("json", FormatterFunc(formatJSON))
+}
+
+func formatJSON(w io.Writer, v *View, opts *Options) error {
+	jsonData, err := json.MarshalIndent(v.Data, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(jsonData))
+	return err
+}
@@ -0,0 +1,118 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register("markdown", FormatterFunc(formatMarkdown))
+}
+
+// formatMarkdown writes GitHub-flavored markdown suitable for pasting into
+// tickets and wiki pages.
+func formatMarkdown(w io.Writer, v *View, opts *Options) error {
+	doc, err := viewDocument(v, opts)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	if doc.Title != "" {
+		fmt.Fprintf(&b, "# %s\n", markdownEscape(doc.Title))
+	}
+	for _, block := range doc.Blocks {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		writeMarkdownBlock(&b, block)
+	}
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// viewDocument returns the view's own Document, or a generic one.
+func viewDocument(v *View, opts *Options) (*Document, error) {
+	if v.Document != nil {
+		return v.Document(), nil
+	}
+	return genericDocument(v, opts)
+}
+
+func writeMarkdownBlock(b *strings.Builder, block Block) {
+	switch bl := block.(type) {
+	case Heading:
+		level := max(bl.Level, 2)
+		fmt.Fprintf(b, "%s %s\n", strings.Repeat("#", level), markdownEscape(bl.Text))
+
+	case Paragraph:
+		fmt.Fprintf(b, "%s\n", markdownText(bl.Text))
+
+	case Fields:
+		for _, f := range bl {
+			if f.Value.String() == "" {
+				continue
+			}
+			value := markdownText(f.Value)
+			if f.Tone != ToneNone {
+				value = "**" + value + "**"
+			}
+			fmt.Fprintf(b, "- **%s:** %s\n", markdownEscape(f.Label), value)
+		}
+
+	case List:
+		for _, item := range bl {
+			fmt.Fprintf(b, "- %s\n", markdownText(item))
+		}
+
+	case Checklist:
+		for _, item := range bl {
+			fmt.Fprintf(b, "- [ ] %s\n", markdownText(item))
+		}
+
+	case Table:
+		headers := make([]string, len(bl.Headers))
+		rule := make([]string, len(bl.Headers))
+		for i, h := range bl.Headers {
+			headers[i] = markdownCell(Plain(h))
+			rule[i] = "---"
+		}
+		fmt.Fprintf(b, "| %s |\n", strings.Join(headers, " | "))
+		fmt.Fprintf(b, "| %s |\n", strings.Join(rule, " | "))
+		for _, row := range bl.Rows {
+			cells := make([]string, len(row))
+			for i, cell := range row {
+				cells[i] = markdownCell(cell)
+			}
+			fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+		}
+	}
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `[`, `\[`, `]`, `\]`, `<`, `\<`, `|`, `\|`,
+)
+
+func markdownEscape(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+func markdownText(t Text) string {
+	var b strings.Builder
+	for _, span := range t {
+		text := markdownEscape(span.Text)
+		if span.URL == "" {
+			b.WriteString(text)
+			continue
+		}
+		url := strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(span.URL)
+		fmt.Fprintf(&b, "[%s](%s)", text, url)
+	}
+	return b.String()
+}
+
+// markdownCell keeps a value on one table row.
+func markdownCell(t Text) string {
+	return strings.Join(strings.Fields(markdownText(t)), " ")
+}
@@ -0,0 +1,66 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+func init() {
+	Register("ndjson", FormatterFunc(formatNDJSON))
+}
+
+// formatNDJSON writes one compact JSON value per line: each record for list
+// commands, or the single result object otherwise. Later pages from v.Next
+// are written as they are fetched.
+func formatNDJSON(w io.Writer, v *View, opts *Options) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	payload := v.Payload()
+	if reflect.ValueOf(payload).Kind() != reflect.Slice {
+		return enc.Encode(payload)
+	}
+	if records, ok := rawRecords(v); ok {
+		payload = records
+	}
+
+	for payload != nil {
+		rv := reflect.ValueOf(payload)
+		for i := 0; i < rv.Len(); i++ {
+			if err := enc.Encode(rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		if v.Next == nil {
+			break
+		}
+		var err error
+		if payload, err = v.Next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rawRecords returns the records as the server sent them, when Records is
+// the response's own data array and the response kept its raw JSON. Fields
+// the models do not cover are then written too, as with -o json.
+func rawRecords(v *View) ([]json.RawMessage, bool) {
+	r, ok := v.Data.(interface {
+		RawRecords() ([]json.RawMessage, bool)
+	})
+	if !ok {
+		return nil, false
+	}
+	records, data := reflect.ValueOf(v.Records), reflect.ValueOf(unwrap(v.Data))
+	if records.Kind() != reflect.Slice || data.Kind() != reflect.Slice ||
+		records.Len() != data.Len() || records.Pointer() != data.Pointer() {
+		return nil, false
+	}
+	raw, ok := r.RawRecords()
+	if !ok || len(raw) != records.Len() {
+		return nil, false
+	}
+	return raw, true
+}
@@ -0,0 +1,214 @@
+// Package output renders command results in the formats selected with
+// --output. Every format is registered here by name, so adding one makes it
+// available to every command.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// View is what a command hands to the renderer: the decoded response, the
+// row-shaped records inside it, if any, and how the table format should
+// show it.
+type View struct {
+	Data interface{}
+	// Records is a slice of row values for list-style commands. Row-based
+	// formats such as csv refuse views without it.
+	Records interface{}
+	// Columns lays out Records as an aligned table. Commands with a single
+	// result set Table instead, which prints it in the command's own style.
+	Columns []Column
+	Table   func(w io.Writer)
+	// Document lays out a single result as a report for the markdown and
+	// html formats. Views without one get a generic layout.
+	Document func() *Document
+	// Sheets splits the result into worksheets for the xlsx format, such
+	// as one per jurisdiction. Without it xlsx writes Records to one sheet.
+	Sheets func() []Sheet
+	// Empty is printed by the table format when Records has no rows.
+	Empty string
+	// Next, when set, returns the records of the following page of a
+	// paginated list, or nil once there are no more. The ndjson format
+	// writes each page as it arrives; other formats see only Records, so
+	// commands collect every page into Records for them instead.
+	Next func() (interface{}, error)
+}
+
+// Payload returns what the response is about, without the API's
+// {"success": ..., "data": ...} envelope: Records when the command set them,
+// otherwise the envelope's data. Data that is not an envelope is returned as
+// is.
+func (v *View) Payload() interface{} {
+	if v.Records != nil {
+		return v.Records
+	}
+	return unwrap(v.Data)
+}
+
+// unwrap returns the value of the field tagged json:"data" when v is an API
+// response envelope, whether typed or decoded into a map.
+func unwrap(v interface{}) interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		_, hasSuccess := m["success"]
+		if data, hasData := m["data"]; hasSuccess && hasData {
+			return data
+		}
+		return v
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return v
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return v
+	}
+
+	var data reflect.Value
+	hasSuccess := false
+	for i := 0; i < rv.NumField(); i++ {
+		name, _ := jsonFieldName(rv.Type().Field(i))
+		switch name {
+		case "data":
+			data = rv.Field(i)
+		case "success":
+			hasSuccess = true
+		}
+	}
+	if data.IsValid() && hasSuccess {
+		return data.Interface()
+	}
+	return v
+}
+
+// Options carries the user's rendering settings to formatters.
+type Options struct {
+	// Template is executed by the template format.
+	Template *template.Template
+
+	// Columns, SortBy, Wide and NoHeaders shape list tables. SortBy may
+	// start with "-" for descending order.
+	Columns   []string
+	SortBy    string
+	Wide      bool
+	NoHeaders bool
+	// Width is the terminal width tables are fitted to, or 0 for no limit.
+	Width int
+
+	// Params describes the request, for formats with a report footer.
+	Params Fields
+}
+
+// Formatter writes a View in one output format.
+type Formatter interface {
+	Format(w io.Writer, v *View, opts *Options) error
+}
+
+// FormatterFunc adapts a function to the Formatter interface.
+type FormatterFunc func(w io.Writer, v *View, opts *Options) error
+
+func (f FormatterFunc) Format(w io.Writer, v *View, opts *Options) error {
+	return f(w, v, opts)
+}
+
+// UnsupportedError is returned when a format cannot represent a command's
+// result, such as csv for a single record.
+type UnsupportedError struct {
+	Format string
+	Reason string
+}
+
+func (e *UnsupportedError) Error() string {
+	return e.Format + " output " + e.Reason
+}
+
+var formatters = map[string]Formatter{}
+
+// Register makes a format available under name. It panics if the name is
+// already taken, since that can only be a programming error.
+func Register(name string, f Formatter) {
+	if _, dup := formatters[name]; dup {
+		panic("output: format registered twice: " + name)
+	}
+	formatters[name] = f
+}
+
+// Names lists the registered formats in alphabetical order.
+func Names() []string {
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the formatter registered under name.
+func Lookup(name string) (Formatter, error) {
+	f, ok := formatters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q (supported: %s)", name, strings.Join(Names(), ", "))
+	}
+	return f, nil
+}
+
+// Render writes v to w in the named format. opts may be nil.
+func Render(w io.Writer, format string, v *View, opts *Options) error {
+	f, err := Lookup(format)
+	if err != nil {
+		return err
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	return f.Format(w, v, opts)
+}
+
+// extensions maps file extensions to the format --output-file infers from
+// them.
+var extensions = map[string]string{
+	".json":     "json",
+	".yaml":     "yaml",
+	".yml":      "yaml",
+	".csv":      "csv",
+	".tsv":      "tsv",
+	".ndjson":   "ndjson",
+	".jsonl":    "ndjson",
+	".md":       "markdown",
+	".markdown": "markdown",
+	".html":     "html",
+	".htm":      "html",
+	".xlsx":     "xlsx",
+	".txt":      "table",
+}
+
+// FormatForFile returns the format implied by name's extension, or "" when
+// the extension is not recognized.
+func FormatForFile(name string) string {
+	return extensions[strings.ToLower(filepath.Ext(name))]
+}
+
+// Normalize converts typed response structs into the plain maps, slices and
+// scalars encoding/json would produce, so formats that walk the data see
+// the same field names as JSON output.
+func Normalize(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,93 @@
+package output
+
+import (
+	"fmt"
+	"io"
+)
+
+func init() {
+	Register("table", FormatterFunc(formatTable))
+}
+
+// formatTable lays out list results as aligned columns and uses the
+// command's own printer for single results. Views built from arbitrary
+// data, such as a --query result, get a generic layout: scalars as plain
+// text, lists of objects as columns and single objects as key: value lines.
+func formatTable(w io.Writer, v *View, opts *Options) error {
+	if v.Columns != nil {
+		return writeGrid(w, v.Payload(), v.Columns, v.Empty, opts)
+	}
+
+	if v.Table != nil {
+		if err := checkUnshaped(opts); err != nil {
+			return err
+		}
+		v.Table(w)
+		return nil
+	}
+
+	payload, err := Normalize(v.Payload())
+	if err != nil {
+		return err
+	}
+	if list, ok := payload.([]interface{}); ok && !isScalarList(list) {
+		return writeGrid(w, list, nil, "", opts)
+	}
+	if err := checkUnshaped(opts); err != nil {
+		return err
+	}
+
+	switch p := payload.(type) {
+	case []interface{}:
+		for _, item := range p {
+			fmt.Fprintln(w, scalarText(item))
+		}
+		return nil
+
+	case map[string]interface{}:
+		columns, rows := Flatten(p)
+		for _, col := range columns {
+			fmt.Fprintf(w, "%s: %s\n", col, rows[0][col])
+		}
+		return nil
+	}
+
+	_, err = fmt.Fprintln(w, scalarText(payload))
+	return err
+}
+
+// checkUnshaped rejects --columns and --sort-by for results that are not
+// laid out as columns.
+func checkUnshaped(opts *Options) error {
+	if len(opts.Columns) > 0 || opts.SortBy != "" {
+		return &UnsupportedError{Format: "table", Reason: "only supports --columns and --sort-by for lists"}
+	}
+	return nil
+}
+
+func isScalarList(items []interface{}) bool {
+	for _, item := range items {
+		switch item.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		}
+	}
+	return true
+}
+
+// scalarText prints strings without JSON quoting, like jq -r.
+func scalarText(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return s
+	case map[string]interface{}, []interface{}:
+		return encodeCellValue(s)
+	}
+	columns, rows := Flatten([]interface{}{v})
+	if len(columns) == 0 {
+		return ""
+	}
+	return rows[0][columns[0]]
+}
@@ -0,0 +1,179 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+func init() {
+	Register("template", FormatterFunc(formatTemplate))
+}
+
+// NewTemplate parses a Go template with the helper functions available to
+// --template.
+func NewTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(TemplateFuncs()).Parse(text)
+}
+
+// TemplateFuncs returns the helpers available inside output templates.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"join":     templateJoin,
+		"truncate": Truncate,
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+		"date":     templateDate,
+		"money":    templateMoney,
+		"json":     templateJSON,
+		"default":  templateDefault,
+	}
+}
+
+// formatTemplate executes the template against the result's payload, once
+// per record for list commands. Each execution ends with a newline unless
+// the template already printed one.
+func formatTemplate(w io.Writer, v *View, opts *Options) error {
+	if opts.Template == nil {
+		return errors.New("template output requires --template or --template-file")
+	}
+
+	payload, err := Normalize(v.Payload())
+	if err != nil {
+		return err
+	}
+
+	items, ok := payload.([]interface{})
+	if !ok {
+		items = []interface{}{payload}
+	}
+
+	var buf bytes.Buffer
+	for _, item := range items {
+		buf.Reset()
+		if err := opts.Template.Execute(&buf, item); err != nil {
+			return err
+		}
+		if buf.Len() > 0 && buf.Bytes()[buf.Len()-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Truncate shortens s to at most n characters, ending with "..." when cut.
+// It counts runes, so multi-byte characters are never split.
+func Truncate(n int, s string) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	if n <= 3 {
+		return string([]rune(s)[:n])
+	}
+	return string([]rune(s)[:n-3]) + "..."
+}
+
+// templateJoin joins a list with sep: {{ .states | join ", " }}.
+func templateJoin(sep string, list interface{}) string {
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Sprint(list)
+	}
+	parts := make([]string, rv.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(rv.Index(i).Interface())
+	}
+	return strings.Join(parts, sep)
+}
+
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// templateDate reformats a timestamp using a Go layout:
+// {{ .timestamp | date "Jan 2, 2006" }}. Values it cannot parse are
+// returned unchanged.
+func templateDate(layout string, value interface{}) string {
+	s := fmt.Sprint(value)
+	for _, l := range dateLayouts {
+		if t, err := time.Parse(l, s); err == nil {
+			return t.Format(layout)
+		}
+	}
+	return s
+}
+
+// templateMoney formats a number as US dollars with thousands separators:
+// 12345.5 becomes $12,345.50.
+func templateMoney(value interface{}) string {
+	var f float64
+	switch n := value.(type) {
+	case float64:
+		f = n
+	case int:
+		f = float64(n)
+	case int64:
+		f = float64(n)
+	case string:
+		parsed, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return n
+		}
+		f = parsed
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
+	return FormatMoney(f)
+}
+
+// FormatMoney formats f as US dollars with thousands separators.
+func FormatMoney(f float64) string {
+	sign := ""
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+
+	whole, frac, _ := strings.Cut(strconv.FormatFloat(f, 'f', 2, 64), ".")
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return sign + "$" + b.String() + "." + frac
+}
+
+func templateJSON(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+// templateDefault returns def when value is missing or empty:
+// {{ .jurisdiction | default "-" }}.
+func templateDefault(def, value interface{}) interface{} {
+	if value == nil {
+		return def
+	}
+	if s, ok := value.(string); ok && s == "" {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,25 @@
+package output
+
+import "testing"
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		n    int
+		s    string
+		want string
+	}{
+		{10, "short", "short"},
+		{5, "exact", "exact"},
+		{8, "a longer sentence", "a lon..."},
+		{6, "naïve café au lait", "naï..."},
+		{4, "日本語のテキスト", "日..."},
+		{3, "abcdef", "abc"},
+		{0, "abc", ""},
+		{5, "", ""},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.n, tt.s); got != tt.want {
+			t.Errorf("Truncate(%d, %q) = %q, want %q", tt.n, tt.s, got, tt.want)
+		}
+	}
+}
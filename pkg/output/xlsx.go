@@ -0,0 +1,307 @@
+package output
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+func init() {
+	Register("xlsx", FormatterFunc(formatXLSX))
+}
+
+// Sheet is one worksheet of a spreadsheet export.
+type Sheet struct {
+	Name    string
+	Records interface{}
+	// Columns picks and names the sheet's columns. Without it every
+	// flattened field is included, as in csv output.
+	Columns []Column
+}
+
+// Cell styles, indexes into cellXfs in xlsxStyles.
+const (
+	styleDefault = iota
+	styleHeader
+	styleDate
+	styleDateTime
+)
+
+const (
+	xlsxMaxSheetName = 31
+	xlsxMaxCellText  = 32767
+	xlsxMaxColWidth  = 60
+)
+
+// formatXLSX writes an Excel workbook with one sheet per View.Sheets entry,
+// or a single sheet of Records. Numbers, booleans and ISO dates are written
+// as typed cells and every sheet's header row is frozen.
+func formatXLSX(w io.Writer, v *View, opts *Options) error {
+	var sheets []Sheet
+	switch {
+	case v.Sheets != nil:
+		sheets = v.Sheets()
+	case v.Records != nil:
+		sheets = []Sheet{{Name: "Results", Records: v.Records}}
+	default:
+		return &UnsupportedError{Format: "xlsx", Reason: "is only available for list commands"}
+	}
+	if len(sheets) == 0 {
+		sheets = []Sheet{{Name: "Results"}}
+	}
+
+	zw := zip.NewWriter(w)
+	names := sheetNames(sheets)
+
+	files := []struct {
+		name string
+		body func(io.Writer) error
+	}{
+		{"[Content_Types].xml", func(w io.Writer) error { return writeContentTypes(w, len(sheets)) }},
+		{"_rels/.rels", func(w io.Writer) error { _, err := io.WriteString(w, xlsxRootRels); return err }},
+		{"xl/workbook.xml", func(w io.Writer) error { return writeWorkbook(w, names) }},
+		{"xl/_rels/workbook.xml.rels", func(w io.Writer) error { return writeWorkbookRels(w, len(sheets)) }},
+		{"xl/styles.xml", func(w io.Writer) error { _, err := io.WriteString(w, xlsxStyles); return err }},
+	}
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if err := f.body(fw); err != nil {
+			return err
+		}
+	}
+
+	for i, sheet := range sheets {
+		fw, err := zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+		if err := writeSheet(fw, sheet); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// sheetNames makes names Excel accepts: at most 31 characters, none of
+// []:*?/\ and unique ignoring case.
+func sheetNames(sheets []Sheet) []string {
+	clean := strings.NewReplacer("[", "(", "]", ")", ":", "-", "*", "-", "?", "", "/", "-", `\`, "-")
+	seen := map[string]bool{}
+	names := make([]string, len(sheets))
+	for i, sheet := range sheets {
+		base := strings.TrimSpace(clean.Replace(sheet.Name))
+		if base == "" {
+			base = fmt.Sprintf("Sheet%d", i+1)
+		}
+		name := truncateRunes(base, xlsxMaxSheetName)
+		for n := 2; seen[strings.ToLower(name)]; n++ {
+			suffix := fmt.Sprintf(" (%d)", n)
+			name = truncateRunes(base, xlsxMaxSheetName-len(suffix)) + suffix
+		}
+		seen[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+func writeSheet(w io.Writer, sheet Sheet) error {
+	fields, rows := flattenCells(sheet.Records)
+
+	columns := sheet.Columns
+	if columns == nil {
+		columns = make([]Column, len(fields))
+		for i, field := range fields {
+			columns[i] = Column{Field: field, Header: field}
+		}
+	}
+
+	widths := make([]int, len(columns))
+	for j, col := range columns {
+		widths[j] = utf8.RuneCountInString(col.header())
+		for _, row := range rows {
+			widths[j] = max(widths[j], utf8.RuneCountInString(row[col.Field].text))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0">`)
+	if len(columns) > 0 {
+		b.WriteString(`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/><selection pane="bottomLeft"/>`)
+	}
+	b.WriteString(`</sheetView></sheetViews>`)
+
+	if len(columns) > 0 {
+		b.WriteString(`<cols>`)
+		for j, width := range widths {
+			width = min(max(width, 8), xlsxMaxColWidth) + 2
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, j+1, j+1, width)
+		}
+		b.WriteString(`</cols>`)
+	}
+
+	b.WriteString(`<sheetData>`)
+	if len(columns) > 0 {
+		b.WriteString(`<row r="1">`)
+		for j, col := range columns {
+			writeTextCell(&b, cellRef(j, 1), col.header(), styleHeader)
+		}
+		b.WriteString(`</row>`)
+	}
+	for i, row := range rows {
+		fmt.Fprintf(&b, `<row r="%d">`, i+2)
+		for j, col := range columns {
+			writeCell(&b, cellRef(j, i+2), row[col.Field])
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData>`)
+
+	if len(columns) > 0 {
+		fmt.Fprintf(&b, `<autoFilter ref="A1:%s"/>`, cellRef(len(columns)-1, len(rows)+1))
+	}
+	b.WriteString(`</worksheet>`)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeCell(b *strings.Builder, ref string, c cell) {
+	switch {
+	case c.text == "":
+		return
+	case c.kind == numberCell:
+		fmt.Fprintf(b, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(c.num, 'f', -1, 64))
+	case c.kind == boolCell:
+		v := "0"
+		if c.text == "true" {
+			v = "1"
+		}
+		fmt.Fprintf(b, `<c r="%s" t="b"><v>%s</v></c>`, ref, v)
+	default:
+		if serial, style, ok := dateSerial(c.text); ok {
+			fmt.Fprintf(b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, strconv.FormatFloat(serial, 'f', -1, 64))
+			return
+		}
+		writeTextCell(b, ref, c.text, styleDefault)
+	}
+}
+
+func writeTextCell(b *strings.Builder, ref, text string, style int) {
+	if len(text) > xlsxMaxCellText {
+		text = truncateRunes(text, xlsxMaxCellText)
+	}
+	fmt.Fprintf(b, `<c r="%s" t="inlineStr"`, ref)
+	if style != styleDefault {
+		fmt.Fprintf(b, ` s="%d"`, style)
+	}
+	b.WriteString(`><is><t xml:space="preserve">`)
+	xml.EscapeText(b, []byte(text))
+	b.WriteString(`</t></is></c>`)
+}
+
+// excelEpoch is day zero of Excel's 1900 date system, as seen from dates
+// after February 1900.
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// dateSerial converts an ISO date (2024-01-31) or RFC 3339 timestamp into
+// an Excel date serial, keeping the timestamp's own wall-clock time.
+func dateSerial(s string) (float64, int, bool) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t.Sub(excelEpoch).Hours() / 24, styleDate, true
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+		return wall.Sub(excelEpoch).Seconds() / 86400, styleDateTime, true
+	}
+	return 0, 0, false
+}
+
+// cellRef returns the A1-style reference for a zero-based column and a
+// one-based row.
+func cellRef(col, row int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name + strconv.Itoa(row)
+}
+
+func writeContentTypes(w io.Writer, sheets int) error {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`</Types>`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeWorkbook(w io.Writer, names []string) error {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, name := range names {
+		b.WriteString(`<sheet name="`)
+		xml.EscapeText(&b, []byte(name))
+		fmt.Fprintf(&b, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeWorkbookRels(w io.Writer, sheets int) error {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheets+1)
+	b.WriteString(`</Relationships>`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// xlsxStyles defines the cell styles in the order of the style constants.
+const xlsxStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="2"><numFmt numFmtId="164" formatCode="yyyy-mm-dd"/><numFmt numFmtId="165" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="4">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
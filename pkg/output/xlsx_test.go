@@ -0,0 +1,33 @@
+package output
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDateSerial(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+		style int
+		ok    bool
+	}{
+		{"1900-03-01", 61, styleDate, true},
+		{"2024-01-31", 45322, styleDate, true},
+		{"2024-02-29", 45351, styleDate, true},
+		{"2024-01-31T12:00:00Z", 45322.5, styleDateTime, true},
+		// The wall-clock time is kept, not converted to UTC.
+		{"2024-01-31T18:00:00-05:00", 45322.75, styleDateTime, true},
+		{"2024-01-31 12:00", 0, 0, false},
+		{"2024-13-01", 0, 0, false},
+		{"01/31/2024", 0, 0, false},
+		{"76942", 0, 0, false},
+		{"", 0, 0, false},
+	}
+	for _, tt := range tests {
+		got, style, ok := dateSerial(tt.value)
+		if ok != tt.ok || style != tt.style || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("dateSerial(%q) = %v, %d, %t; want %v, %d, %t", tt.value, got, style, ok, tt.want, tt.style, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package output
+
+import (
+	"io"
+
+	"go.yaml.in/yaml/v3"
+)
+
+func init() {
+	Register("yaml", FormatterFunc(formatYAML))
+}
+
+func formatYAML(w io.Writer, v *View, opts *Options) error {
+	// The response types only carry json tags, so go through Normalize to
+	// keep the same snake_case keys as JSON output.
+	data, err := Normalize(v.Data)
+	if err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(data); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,441 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokDot
+	tokLBrack
+	tokRBrack
+	tokLParen
+	tokRParen
+	tokPipe
+	tokColon
+	tokQuestion
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of query"
+	}
+	return strconv.Quote(t.text)
+}
+
+type lexer struct {
+	src []rune
+	pos int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: []rune(src)}
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) && unicode.IsSpace(l.src[l.pos]) {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, pos: start}, nil
+	}
+
+	r := l.src[l.pos]
+	single := map[rune]tokenKind{
+		'.': tokDot, '[': tokLBrack, ']': tokRBrack, '(': tokLParen,
+		')': tokRParen, '|': tokPipe, ':': tokColon, '?': tokQuestion,
+	}
+	if kind, ok := single[r]; ok {
+		l.pos++
+		return token{kind: kind, text: string(r), pos: start}, nil
+	}
+
+	switch {
+	case r == '"':
+		return l.lexString()
+
+	case r == '=' || r == '!' || r == '<' || r == '>':
+		l.pos++
+		if l.pos < len(l.src) && l.src[l.pos] == '=' {
+			l.pos++
+		}
+		op := string(l.src[start:l.pos])
+		if op == "=" || op == "!" {
+			return token{}, fmt.Errorf("unexpected %q at offset %d", op, start)
+		}
+		return token{kind: tokOp, text: op, pos: start}, nil
+
+	case unicode.IsDigit(r) || (r == '-' && l.pos+1 < len(l.src) && unicode.IsDigit(l.src[l.pos+1])):
+		l.pos++
+		for l.pos < len(l.src) && (unicode.IsDigit(l.src[l.pos]) || l.src[l.pos] == '.') {
+			l.pos++
+		}
+		text := string(l.src[start:l.pos])
+		n, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return token{}, fmt.Errorf("invalid number %q at offset %d", text, start)
+		}
+		return token{kind: tokNumber, text: text, num: n, pos: start}, nil
+
+	case r == '_' || unicode.IsLetter(r):
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || unicode.IsLetter(l.src[l.pos]) || unicode.IsDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokIdent, text: string(l.src[start:l.pos]), pos: start}, nil
+	}
+
+	return token{}, fmt.Errorf("unexpected %q at offset %d", r, start)
+}
+
+func (l *lexer) lexString() (token, error) {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos += 2
+			continue
+		case '"':
+			l.pos++
+			text := string(l.src[start:l.pos])
+			s, err := strconv.Unquote(text)
+			if err != nil {
+				return token{}, fmt.Errorf("invalid string %s at offset %d", text, start)
+			}
+			return token{kind: tokString, text: s, pos: start}, nil
+		}
+		l.pos++
+	}
+	return token{}, fmt.Errorf("unterminated string at offset %d", start)
+}
+
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) expect(kind tokenKind, what string) error {
+	if p.tok.kind != kind {
+		return fmt.Errorf("expected %s but found %s at offset %d", what, p.tok, p.tok.pos)
+	}
+	return p.advance()
+}
+
+func (p *parser) isKeyword(word string) bool {
+	return p.tok.kind == tokIdent && p.tok.text == word
+}
+
+func (p *parser) parsePipe() (node, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokPipe {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		left = pipe{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	return p.parseBinary("or", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseBinary("and", p.parseCompare)
+}
+
+func (p *parser) parseBinary(word string, operand func() (node, error)) (node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword(word) {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: word, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseCompare() (node, error) {
+	left, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokOp {
+		return left, nil
+	}
+	op := p.tok.text
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	right, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	return binary{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.tok.kind {
+		case tokDot:
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			switch p.tok.kind {
+			case tokIdent, tokString:
+				n = field{target: n, name: p.tok.text}
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			case tokLBrack:
+				// .foo.[0] is the same as .foo[0]
+			default:
+				return nil, fmt.Errorf("expected field name after '.' at offset %d", p.tok.pos)
+			}
+
+		case tokLBrack:
+			if n, err = p.parseBracket(n); err != nil {
+				return nil, err
+			}
+
+		case tokQuestion:
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			n = optional(n)
+
+		default:
+			return n, nil
+		}
+	}
+}
+
+// optional marks the last access in n as tolerant of type errors.
+func optional(n node) node {
+	switch v := n.(type) {
+	case field:
+		v.optional = true
+		return v
+	case index:
+		v.optional = true
+		return v
+	case slice:
+		v.optional = true
+		return v
+	case iterate:
+		v.optional = true
+		return v
+	}
+	return n
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokDot:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		switch p.tok.kind {
+		case tokIdent, tokString:
+			name := p.tok.text
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			return field{target: identity{}, name: name}, nil
+		case tokLBrack:
+			return p.parseBracket(identity{})
+		}
+		return identity{}, nil
+
+	case tokString:
+		return literal{value: tok.text}, p.advance()
+
+	case tokNumber:
+		return literal{value: tok.num}, p.advance()
+
+	case tokLBrack:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind == tokRBrack {
+			return collect{}, p.advance()
+		}
+		inner, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return collect{inner: inner}, p.expect(tokRBrack, "']'")
+
+	case tokLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(tokRParen, "')'")
+
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return literal{value: true}, p.advance()
+		case "false":
+			return literal{value: false}, p.advance()
+		case "null":
+			return literal{value: nil}, p.advance()
+		}
+
+		takesArg, ok := functions[tok.text]
+		if !ok {
+			return nil, fmt.Errorf("unknown function %q at offset %d", tok.text, tok.pos)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if !takesArg {
+			return call{name: tok.text}, nil
+		}
+		if err := p.expect(tokLParen, "'(' after "+tok.text); err != nil {
+			return nil, err
+		}
+		arg, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return call{name: tok.text, arg: arg}, p.expect(tokRParen, "')'")
+	}
+
+	return nil, fmt.Errorf("unexpected %s at offset %d", tok, tok.pos)
+}
+
+// parseBracket parses the part of target[...] after target, starting at '['.
+func (p *parser) parseBracket(target node) (node, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind == tokRBrack {
+		return iterate{target: target}, p.advance()
+	}
+
+	// Slices only take integer bounds: .[1:3], .[:2], .[-2:].
+	var from *int
+	if p.tok.kind == tokNumber || p.tok.kind == tokColon {
+		if p.tok.kind == tokNumber {
+			numTok := p.tok
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.tok.kind == tokRBrack {
+				if _, err := integer(numTok); err != nil {
+					return nil, err
+				}
+				return index{target: target, key: literal{value: numTok.num}}, p.advance()
+			}
+			n, err := sliceBound(numTok)
+			if err != nil {
+				return nil, err
+			}
+			from = &n
+		}
+		if p.tok.kind == tokColon {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			var to *int
+			if p.tok.kind == tokNumber {
+				n, err := sliceBound(p.tok)
+				if err != nil {
+					return nil, err
+				}
+				to = &n
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			}
+			return slice{target: target, from: from, to: to}, p.expect(tokRBrack, "']'")
+		}
+		return nil, fmt.Errorf("expected ']' or ':' at offset %d", p.tok.pos)
+	}
+
+	key, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	return index{target: target, key: key}, p.expect(tokRBrack, "']'")
+}
+
+// integer returns the value of a number token used as an index. Fractions
+// and values outside the int range are rejected rather than converted,
+// which would truncate or overflow.
+func integer(tok token) (int, error) {
+	if tok.num != math.Trunc(tok.num) {
+		return 0, fmt.Errorf("index %s at offset %d is not an integer", tok.text, tok.pos)
+	}
+	if tok.num < math.MinInt || tok.num >= math.MaxInt {
+		return 0, fmt.Errorf("index %s at offset %d is out of range", tok.text, tok.pos)
+	}
+	return int(tok.num), nil
+}
+
+// sliceBound returns the value of a number token used as a slice bound.
+// Bounds beyond the int range are clamped to it, since the slice clamps
+// them to the array anyway.
+func sliceBound(tok token) (int, error) {
+	switch {
+	case tok.num != math.Trunc(tok.num):
+		return 0, fmt.Errorf("slice bound %s at offset %d is not an integer", tok.text, tok.pos)
+	case tok.num >= math.MaxInt:
+		return math.MaxInt, nil
+	case tok.num <= math.MinInt:
+		return math.MinInt, nil
+	}
+	return int(tok.num), nil
+}
@@ -0,0 +1,553 @@
+// Package query implements the subset of jq used by --query to pick values
+// out of a decoded response.
+//
+// Supported syntax:
+//
+//	.                  the input itself
+//	.foo  ."foo"       object field (null when missing)
+//	.[0]  .[-1]        array element
+//	.[2:5]             array slice
+//	.[]                every element of an array or value of an object
+//	a | b              feed every output of a into b
+//	[ expr ]           collect outputs into an array
+//	a == b, !=, <, <=, >, >=, and, or
+//	length, keys, first, last, not, select(expr), map(expr)
+//	"string", 42, true, false, null
+//
+// Values of different types are ordered as in jq: null, false, true,
+// numbers, strings, arrays, objects. A trailing ? suppresses type errors,
+// as in jq.
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Query is a parsed expression.
+type Query struct {
+	src  string
+	root node
+}
+
+// Parse compiles a jq-style expression.
+func Parse(src string) (*Query, error) {
+	p := &parser{lex: newLexer(src)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	root, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %s at offset %d", p.tok, p.tok.pos)
+	}
+	return &Query{src: src, root: root}, nil
+}
+
+func (q *Query) String() string {
+	return q.src
+}
+
+// Run evaluates the query against v, which must be made of the values
+// encoding/json decodes into: maps, slices, strings, float64, bool and nil.
+// It returns every output in order.
+func (q *Query) Run(v interface{}) ([]interface{}, error) {
+	return q.root.eval(v)
+}
+
+type node interface {
+	eval(in interface{}) ([]interface{}, error)
+}
+
+type identity struct{}
+
+func (identity) eval(in interface{}) ([]interface{}, error) {
+	return []interface{}{in}, nil
+}
+
+type literal struct {
+	value interface{}
+}
+
+func (l literal) eval(interface{}) ([]interface{}, error) {
+	return []interface{}{l.value}, nil
+}
+
+// pipe feeds every output of left into right.
+type pipe struct {
+	left, right node
+}
+
+func (p pipe) eval(in interface{}) ([]interface{}, error) {
+	lefts, err := p.left.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, l := range lefts {
+		rights, err := p.right.eval(l)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rights...)
+	}
+	return out, nil
+}
+
+// field looks up a key on each output of target.
+type field struct {
+	target   node
+	name     string
+	optional bool
+}
+
+func (f field) eval(in interface{}) ([]interface{}, error) {
+	targets, err := f.target.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, t := range targets {
+		switch obj := t.(type) {
+		case nil:
+			out = append(out, nil)
+		case map[string]interface{}:
+			out = append(out, obj[f.name])
+		default:
+			if !f.optional {
+				return nil, fmt.Errorf("cannot index %s with %q", typeName(t), f.name)
+			}
+		}
+	}
+	return out, nil
+}
+
+// index looks up an array element or object key computed by an expression.
+type index struct {
+	target   node
+	key      node
+	optional bool
+}
+
+func (ix index) eval(in interface{}) ([]interface{}, error) {
+	targets, err := ix.target.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := ix.key.eval(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []interface{}
+	for _, t := range targets {
+		for _, k := range keys {
+			v, err := lookup(t, k)
+			if err != nil {
+				if ix.optional {
+					continue
+				}
+				return nil, err
+			}
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func lookup(t, k interface{}) (interface{}, error) {
+	switch c := t.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		if s, ok := k.(string); ok {
+			return c[s], nil
+		}
+	case []interface{}:
+		if f, ok := k.(float64); ok {
+			// Check the range before converting, as a float too large
+			// for an int does not convert to one.
+			if f < 0 {
+				f += float64(len(c))
+			}
+			if f < 0 || f >= float64(len(c)) {
+				return nil, nil
+			}
+			return c[int(f)], nil
+		}
+	}
+	return nil, fmt.Errorf("cannot index %s with %s", typeName(t), typeName(k))
+}
+
+// slice returns part of an array: .[from:to].
+type slice struct {
+	target   node
+	from, to *int
+	optional bool
+}
+
+func (s slice) eval(in interface{}) ([]interface{}, error) {
+	targets, err := s.target.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, t := range targets {
+		arr, ok := t.([]interface{})
+		if !ok {
+			if t == nil {
+				out = append(out, nil)
+				continue
+			}
+			if s.optional {
+				continue
+			}
+			return nil, fmt.Errorf("cannot slice %s", typeName(t))
+		}
+		from, to := 0, len(arr)
+		if s.from != nil {
+			from = clampIndex(*s.from, len(arr))
+		}
+		if s.to != nil {
+			to = clampIndex(*s.to, len(arr))
+		}
+		if to < from {
+			to = from
+		}
+		out = append(out, append([]interface{}{}, arr[from:to]...))
+	}
+	return out, nil
+}
+
+func clampIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > n {
+		return n
+	}
+	return i
+}
+
+// iterate emits every element of an array or every value of an object.
+// Object values come out in key order.
+type iterate struct {
+	target   node
+	optional bool
+}
+
+func (it iterate) eval(in interface{}) ([]interface{}, error) {
+	targets, err := it.target.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, t := range targets {
+		switch c := t.(type) {
+		case []interface{}:
+			out = append(out, c...)
+		case map[string]interface{}:
+			for _, k := range sortedKeys(c) {
+				out = append(out, c[k])
+			}
+		default:
+			if !it.optional {
+				return nil, fmt.Errorf("cannot iterate over %s", typeName(t))
+			}
+		}
+	}
+	return out, nil
+}
+
+// collect gathers every output of inner into one array: [ expr ].
+type collect struct {
+	inner node
+}
+
+func (c collect) eval(in interface{}) ([]interface{}, error) {
+	if c.inner == nil {
+		return []interface{}{[]interface{}{}}, nil
+	}
+	vals, err := c.inner.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	if vals == nil {
+		vals = []interface{}{}
+	}
+	return []interface{}{vals}, nil
+}
+
+type binary struct {
+	op          string
+	left, right node
+}
+
+func (b binary) eval(in interface{}) ([]interface{}, error) {
+	lefts, err := b.left.eval(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []interface{}
+	for _, l := range lefts {
+		// and/or short-circuit like jq.
+		if b.op == "and" && !truthy(l) {
+			out = append(out, false)
+			continue
+		}
+		if b.op == "or" && truthy(l) {
+			out = append(out, true)
+			continue
+		}
+
+		rights, err := b.right.eval(in)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rights {
+			v, err := compare(b.op, l, r)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func compare(op string, l, r interface{}) (bool, error) {
+	switch op {
+	case "and", "or":
+		return truthy(r), nil
+	case "==":
+		return reflect.DeepEqual(l, r), nil
+	case "!=":
+		return !reflect.DeepEqual(l, r), nil
+	}
+
+	c := order(l, r)
+	switch op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	case ">=":
+		return c >= 0, nil
+	}
+	return false, fmt.Errorf("unknown operator %s", op)
+}
+
+// order compares two values the way jq sorts them: null, false, true,
+// numbers, strings, arrays, then objects, with values of the same type
+// compared by content.
+func order(l, r interface{}) int {
+	if lr, rr := typeRank(l), typeRank(r); lr != rr {
+		return cmpInt(lr, rr)
+	}
+	switch lv := l.(type) {
+	case float64:
+		rv := r.(float64)
+		switch {
+		case lv < rv:
+			return -1
+		case lv > rv:
+			return 1
+		}
+	case string:
+		return strings.Compare(lv, r.(string))
+	case []interface{}:
+		rv := r.([]interface{})
+		for i := 0; i < len(lv) && i < len(rv); i++ {
+			if c := order(lv[i], rv[i]); c != 0 {
+				return c
+			}
+		}
+		return cmpInt(len(lv), len(rv))
+	case map[string]interface{}:
+		rv := r.(map[string]interface{})
+		lk, rk := sortedKeys(lv), sortedKeys(rv)
+		if c := order(stringsToValues(lk), stringsToValues(rk)); c != 0 {
+			return c
+		}
+		for _, k := range lk {
+			if c := order(lv[k], rv[k]); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+func typeRank(v interface{}) int {
+	switch v := v.(type) {
+	case nil:
+		return 0
+	case bool:
+		if v {
+			return 2
+		}
+		return 1
+	case float64:
+		return 3
+	case string:
+		return 4
+	case []interface{}:
+		return 5
+	}
+	return 6
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func stringsToValues(ss []string) []interface{} {
+	vals := make([]interface{}, len(ss))
+	for i, s := range ss {
+		vals[i] = s
+	}
+	return vals
+}
+
+// call is a built-in function, with an optional argument expression.
+type call struct {
+	name string
+	arg  node
+}
+
+func (c call) eval(in interface{}) ([]interface{}, error) {
+	switch c.name {
+	case "length":
+		switch v := in.(type) {
+		case nil:
+			return []interface{}{float64(0)}, nil
+		case string:
+			return []interface{}{float64(len([]rune(v)))}, nil
+		case []interface{}:
+			return []interface{}{float64(len(v))}, nil
+		case map[string]interface{}:
+			return []interface{}{float64(len(v))}, nil
+		}
+		return nil, fmt.Errorf("%s has no length", typeName(in))
+
+	case "keys":
+		obj, ok := in.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s has no keys", typeName(in))
+		}
+		keys := []interface{}{}
+		for _, k := range sortedKeys(obj) {
+			keys = append(keys, k)
+		}
+		return []interface{}{keys}, nil
+
+	case "first", "last":
+		arr, ok := in.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot take %s of %s", c.name, typeName(in))
+		}
+		if len(arr) == 0 {
+			return []interface{}{nil}, nil
+		}
+		if c.name == "first" {
+			return []interface{}{arr[0]}, nil
+		}
+		return []interface{}{arr[len(arr)-1]}, nil
+
+	case "not":
+		return []interface{}{!truthy(in)}, nil
+
+	case "select":
+		conds, err := c.arg.eval(in)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, cond := range conds {
+			if truthy(cond) {
+				out = append(out, in)
+			}
+		}
+		return out, nil
+
+	case "map":
+		arr, ok := in.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot map over %s", typeName(in))
+		}
+		mapped := []interface{}{}
+		for _, item := range arr {
+			vals, err := c.arg.eval(item)
+			if err != nil {
+				return nil, err
+			}
+			mapped = append(mapped, vals...)
+		}
+		return []interface{}{mapped}, nil
+	}
+	return nil, fmt.Errorf("unknown function %s", c.name)
+}
+
+// functions lists the built-ins and whether they take an argument.
+var functions = map[string]bool{
+	"length": false,
+	"keys":   false,
+	"first":  false,
+	"last":   false,
+	"not":    false,
+	"select": true,
+	"map":    true,
+}
+
+func truthy(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	return true
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
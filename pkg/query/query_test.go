@@ -0,0 +1,186 @@
+package query
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const input = `{
+	"success": true,
+	"data": [
+		{"policy_id": "L33831", "jurisdiction": "JM", "status": "active", "codes": ["76942", "20604"], "pages": 4},
+		{"policy_id": "L35036", "jurisdiction": "JH", "status": "active", "codes": ["72148"], "pages": 12},
+		{"policy_id": "L36575", "jurisdiction": "JM", "status": "retired", "codes": [], "pages": null}
+	],
+	"meta": {"pagination": {"page": 1, "total": 3}}
+}`
+
+func decode(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("decoding %s: %v", s, err)
+	}
+	return v
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string // JSON array of every output
+	}{
+		// Paths
+		{"identity", ".success", `[true]`},
+		{"nested field", ".meta.pagination.total", `[3]`},
+		{"quoted field", `.meta."pagination".page`, `[1]`},
+		{"missing field", ".nope", `[null]`},
+		{"field of null", ".nope.deeper", `[null]`},
+		{"index", ".data[0].policy_id", `["L33831"]`},
+		{"negative index", ".data[-1].policy_id", `["L36575"]`},
+		{"index out of range", ".data[9]", `[null]`},
+
+		// Iteration
+		{"iterate array", ".data[].policy_id", `["L33831", "L35036", "L36575"]`},
+		{"iterate object", ".meta.pagination[]", `[1, 3]`},
+		{"iterate nested", ".data[].codes[]", `["76942", "20604", "72148"]`},
+		{"iterate empty", ".data[2].codes[]", `[]`},
+
+		// Slices
+		{"slice", ".data[1:2] | map(.policy_id)", `[["L35036"]]`},
+		{"slice then iterate", "[.data[1:][] | .policy_id]", `[["L35036", "L36575"]]`},
+		{"slice open start", ".data[:1] | length", `[1]`},
+		{"slice negative", ".data[-2:] | map(.policy_id)", `[["L35036", "L36575"]]`},
+		{"slice clamped", ".data[1:99] | length", `[2]`},
+		{"slice beyond int range", ".data[:9999999999999999999] | length", `[3]`},
+		{"slice below int range", ".data[-9999999999999999999:] | length", `[3]`},
+
+		// Pipes and builtins
+		{"pipe", ".data | length", `[3]`},
+		{"collect", "[.data[] | .jurisdiction]", `[["JM", "JH", "JM"]]`},
+		{"map", ".data | map(.pages)", `[[4, 12, null]]`},
+		{"keys", ".meta.pagination | keys", `[["page", "total"]]`},
+		{"first", ".data | first | .policy_id", `["L33831"]`},
+		{"last", ".data | last | .policy_id", `["L36575"]`},
+		{"string length", ".data[0].policy_id | length", `[6]`},
+
+		// select and comparisons
+		{"select equal", `.data[] | select(.jurisdiction == "JM") | .policy_id`, `["L33831", "L36575"]`},
+		{"select not equal", `.data[] | select(.status != "active") | .policy_id`, `["L36575"]`},
+		{"select greater", ".data[] | select(.pages > 4) | .policy_id", `["L35036"]`},
+		{"select at least", ".data[] | select(.pages >= 4) | .policy_id", `["L33831", "L35036"]`},
+		{"select less", ".data[] | select(.pages < 12) | .policy_id", `["L33831", "L36575"]`},
+		{"select and", `.data[] | select(.jurisdiction == "JM" and .status == "active") | .policy_id`, `["L33831"]`},
+		{"select or", `.data[] | select(.jurisdiction == "JH" or .pages == null) | .policy_id`, `["L35036", "L36575"]`},
+		{"select not", `.data[] | select(.status == "active" | not) | .policy_id`, `["L36575"]`},
+		{"select nothing", `.data[] | select(.jurisdiction == "J15")`, `[]`},
+		{"compare strings", `.data[0].policy_id < .data[1].policy_id`, `[true]`},
+		{"compare literal", `42 == 42.0`, `[true]`},
+		{"compare across types", `null < false and false < true and true < 0 and 0 < "" and "" < .data and .data < .meta`, `[true]`},
+		{"compare arrays", `.data[0].codes > .data[1].codes`, `[true]`},
+		{"compare null with number", `.data[2].pages < 0`, `[true]`},
+
+		// Optional
+		{"optional suppresses type error", ".success[]?", `[]`},
+	}
+
+	data := decode(t, input)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			got, err := q.Run(data)
+			if err != nil {
+				t.Fatalf("Run(%q): %v", tt.expr, err)
+			}
+			if got == nil {
+				got = []interface{}{}
+			}
+			if want := decode(t, tt.want); !reflect.DeepEqual(got, want) {
+				gotJSON, _ := json.Marshal(got)
+				t.Errorf("Run(%q) = %s, want %s", tt.expr, gotJSON, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputedIndexOutOfRange(t *testing.T) {
+	data := decode(t, `{"n": 1e300, "m": -1e300, "a": [1, 2]}`)
+	for _, expr := range []string{".a[.n]", ".a[.m]"} {
+		q, err := Parse(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := q.Run(data)
+		if err != nil || !reflect.DeepEqual(got, []interface{}{nil}) {
+			t.Errorf("Run(%q) = %v, %v; want [null]", expr, got, err)
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{".success.field", "boolean"},
+		{".data.policy_id", "array"},
+		{".success[]", "boolean"},
+		{".data[0] | keys | .[\"x\"]", "array"},
+	}
+
+	data := decode(t, input)
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			q, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			if _, err := q.Run(data); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Run(%q) error = %v, want one mentioning %q", tt.expr, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		".data[",
+		".data[0",
+		".data]",
+		"select(.a",
+		`.data | "unterminated`,
+		".data ==",
+		". . .",
+		"|",
+		"@",
+		".data | nosuch",
+		".a, .b",
+		".data[1.5]",
+		".data[9999999999999999999]",
+		".data[0.5:]",
+		".data[:2.5]",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			if q, err := Parse(expr); err == nil {
+				t.Errorf("Parse(%q) = %v, want an error", expr, q)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	const expr = ".data[] | select(.pages > 4)"
+	q, err := Parse(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.String() != expr {
+		t.Errorf("String() = %q, want %q", q.String(), expr)
+	}
+}